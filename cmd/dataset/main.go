@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+func main() {
+	var source = flag.String("source", "enumerate", "Position source: enumerate (every reachable position) or selfplay")
+	var format = flag.String("format", "jsonl", "Output format: jsonl or csv")
+	var out = flag.String("out", "", "Output file (default stdout)")
+	var canonical = flag.Bool("canonical", false, "With -source enumerate, emit one position per symmetry class")
+	var a = flag.String("a", "heuristic", "With -source selfplay, first strategy: "+strings.Join(game.StrategyNames(), ", "))
+	var b = flag.String("b", "random", "With -source selfplay, second strategy")
+	var games = flag.Int("games", 1000, "With -source selfplay, number of games to sample")
+	var seed = flag.Uint64("seed", uint64(time.Now().UnixNano()), "With -source selfplay, random seed")
+	flag.Parse()
+
+	output := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Creating output failed: %v", err)
+		}
+		output = file
+	}
+	buffered := bufio.NewWriter(output)
+
+	writer, err := game.NewDatasetWriter(buffered, game.DatasetFormat(*format))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Ctrl-C stops sampling; the examples written so far are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	count := 0
+	emit := func(example game.Example) error {
+		count++
+		return writer.Write(example)
+	}
+
+	switch *source {
+	case "enumerate":
+		err = game.EnumerateExamples(*canonical, emit)
+	case "selfplay":
+		err = game.SampleExamples(ctx, [2]string{*a, *b}, *games, *seed, emit)
+	default:
+		log.Fatalf("Unknown source: %s (supported: enumerate, selfplay)", *source)
+	}
+	if errors.Is(err, context.Canceled) {
+		log.Printf("Export interrupted: %v", err)
+		err = nil
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if output != os.Stdout {
+		if closeErr := output.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Fatalf("Export failed after %d examples: %v", count, err)
+	}
+	log.Printf("Wrote %d examples", count)
+}
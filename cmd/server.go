@@ -1,37 +1,245 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"mcp-tic-tac-toe/server"
 )
 
+// stringList is a flag that may be repeated or given comma-separated values
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// fatal logs an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// setupLogging makes a stderr handler of the given format and level the
+// default logger. Stdout is left alone: the stdio transport speaks on it.
+func setupLogging(format, level string) error {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("unknown log level: %s (supported: debug, info, warn, error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: logLevel}
+	switch format {
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, opts)))
+	default:
+		return fmt.Errorf("unknown log format: %s (supported: text, json)", format)
+	}
+	return nil
+}
+
 func main() {
-	var transport = flag.String("transport", "stdio", "Transport method: stdio, sse, or http")
-	var addr = flag.String("addr", ":8080", "Address to listen on (for sse/http transport)")
+	var transports stringList
+	flag.Var(&transports, "transport", "Transports to serve: stdio, sse, http, ws, or several comma-separated (default stdio)")
+	var addr = flag.String("addr", ":8080", "Address to listen on, shared by the sse/http/ws transports")
+	var rest = flag.Bool("rest", false, "Serve the REST API under /games on the sse/http/ws transport address")
+	var ui = flag.Bool("ui", false, "Serve the browser UI at /ui/ on the sse/http/ws transport address")
+	var restAddr = flag.String("rest-addr", "", "Also serve the REST API on this address (e.g. alongside stdio)")
+	var webhookURLs stringList
+	flag.Var(&webhookURLs, "webhook", "Webhook URL to POST game events to (repeatable or comma-separated)")
+	var webhookSecret = flag.String("webhook-secret", "", "Secret for signing webhook requests (default $WEBHOOK_SECRET)")
+	var webhookConfig = flag.String("webhook-config", "", "JSON webhook config file; -webhook URLs are added to its list")
+	var webhookDeadLetter = flag.String("webhook-dead-letter", "", "File to append undeliverable webhook events to")
+	var shutdownTimeout = flag.Duration("shutdown-timeout", server.DefaultShutdownTimeout, "How long to wait for running tool calls and open HTTP requests when stopping")
+	var tlsCert = flag.String("tls-cert", "", "PEM certificate file; serves the HTTP listeners over TLS, reloading it when it changes")
+	var tlsKey = flag.String("tls-key", "", "PEM private key file for -tls-cert")
+	var tlsClientCA = flag.String("tls-client-ca", "", "PEM CA bundle; clients must present a certificate it signed")
+	var authMode = flag.String("auth", "", "Authentication for the HTTP listeners: token, jwt or mtls (default none)")
+	var authTokens = flag.String("auth-tokens", "", "File of '<name> <token>' lines for -auth=token")
+	var jwtSecret = flag.String("auth-jwt-secret", "", "HS256 secret for -auth=jwt (default $AUTH_JWT_SECRET)")
+	var jwtIssuer = flag.String("auth-jwt-issuer", "", "Required JWT issuer for -auth=jwt")
+	var jwtAudience = flag.String("auth-jwt-audience", "", "Required JWT audience for -auth=jwt")
+	var rateLimit = flag.Float64("rate-limit", 0, "Sustained tool calls and REST requests per second per client (0 for no limit)")
+	var rateBurst = flag.Int("rate-burst", 0, "Calls a client may make at once before -rate-limit applies (default: the rate, rounded up)")
+	var maxOpenGames = flag.Int("max-open-games", 0, "Games each client may hold until it deletes them (0 for no limit)")
+	var metrics = flag.Bool("metrics", false, "Serve Prometheus metrics at /metrics on the HTTP listeners")
+	var stateFile = flag.String("state-file", "", "JSON file to restore games from at startup and save them to on shutdown")
+	var logFormat = flag.String("log-format", "text", "Log format on stderr: text or json")
+	var logLevel = flag.String("log-level", "info", "Minimum log level: debug, info, warn or error; debug logs every tool call")
+	var auditFile = flag.String("audit-log", "", "JSONL file to record every tool call in")
+	var auditMaxSize = flag.Int64("audit-max-size", 100, "Megabytes the audit log may reach before it is rotated (0 never rotates)")
+	var auditMaxBackups = flag.Int("audit-max-backups", 5, "Rotated audit logs to keep")
 	flag.Parse()
+	if err := setupLogging(*logFormat, *logLevel); err != nil {
+		fatal("Setting up logging failed", "error", err)
+	}
+	if len(transports) == 0 {
+		transports = stringList{server.TransportStdio}
+	}
 
 	// Create the tic-tac-toe MCP server
 	gameServer := server.NewTicTacToeServer()
 
-	log.Printf("Starting MCP Tic-Tac-Toe server with %s transport", *transport)
+	if *stateFile != "" {
+		n, err := gameServer.EnableStateFile(*stateFile)
+		if err != nil {
+			fatal("Loading state failed", "error", err)
+		}
+		slog.Info("Restored games", "count", n, "path", *stateFile)
+	}
+
+	var webhooks server.WebhookConfig
+	if *webhookConfig != "" {
+		cfg, err := server.LoadWebhookConfig(*webhookConfig)
+		if err != nil {
+			fatal("Loading webhook config failed", "error", err)
+		}
+		webhooks = cfg
+	}
+	webhooks.URLs = append(webhooks.URLs, webhookURLs...)
+	if *webhookSecret != "" {
+		webhooks.Secret = *webhookSecret
+	} else if webhooks.Secret == "" {
+		webhooks.Secret = os.Getenv("WEBHOOK_SECRET")
+	}
+	if *webhookDeadLetter != "" {
+		webhooks.DeadLetterFile = *webhookDeadLetter
+	}
+	var dispatcher *server.WebhookDispatcher
+	if len(webhooks.URLs) > 0 {
+		var err error
+		dispatcher, err = gameServer.EnableWebhooks(webhooks)
+		if err != nil {
+			fatal("Starting webhooks failed", "error", err)
+		}
+		slog.Info("Sending game events to webhooks", "count", len(webhooks.URLs))
+	}
 
-	var err error
-	switch *transport {
-	case "stdio":
-		err = gameServer.ServeStdio()
-	case "sse":
-		err = gameServer.ServeSSE(*addr)
-	case "http":
-		err = gameServer.ServeStreamableHTTP(*addr)
+	if *tlsCert != "" || *tlsKey != "" || *tlsClientCA != "" {
+		err := gameServer.EnableTLS(server.TLSOptions{
+			CertFile:     *tlsCert,
+			KeyFile:      *tlsKey,
+			ClientCAFile: *tlsClientCA,
+		})
+		if err != nil {
+			fatal("Setting up TLS failed", "error", err)
+		}
+	}
+
+	switch *authMode {
+	case "":
+	case "token":
+		if *authTokens == "" {
+			fatal("-auth=token needs -auth-tokens")
+		}
+		auth, err := server.LoadTokenFile(*authTokens)
+		if err != nil {
+			fatal("Loading tokens failed", "error", err)
+		}
+		gameServer.EnableAuth(auth)
+	case "jwt":
+		secret := *jwtSecret
+		if secret == "" {
+			secret = os.Getenv("AUTH_JWT_SECRET")
+		}
+		if secret == "" {
+			fatal("-auth=jwt needs -auth-jwt-secret or $AUTH_JWT_SECRET")
+		}
+		gameServer.EnableAuth(&server.JWTAuthenticator{
+			Secret:   []byte(secret),
+			Issuer:   *jwtIssuer,
+			Audience: *jwtAudience,
+			Leeway:   time.Minute,
+		})
+	case "mtls":
+		if *tlsClientCA == "" {
+			fatal("-auth=mtls needs -tls-client-ca")
+		}
+		gameServer.EnableAuth(server.MTLSAuthenticator{})
 	default:
-		log.Fatalf("Unknown transport: %s (supported: stdio, sse, http)", *transport)
+		fatal("Unknown auth mode (supported: token, jwt, mtls)", "auth", *authMode)
+	}
+	if *authMode != "" {
+		slog.Info("HTTP listeners require authentication", "auth", *authMode)
 	}
 
+	gameServer.EnableLimits(server.LimitOptions{
+		Rate:         *rateLimit,
+		Burst:        *rateBurst,
+		MaxOpenGames: *maxOpenGames,
+	})
+
+	if *rest {
+		gameServer.EnableREST()
+	}
+	if *ui && *authMode != "" {
+		// Browsers cannot attach bearer tokens to the UI's event stream
+		slog.Info("Browser UI disabled because it does not support authentication")
+	} else if *ui {
+		gameServer.EnableUI()
+	}
+	if *metrics {
+		gameServer.EnableMetrics()
+	}
+	var audit *server.RotatingFile
+	if *auditFile != "" {
+		var err error
+		audit, err = server.OpenRotatingFile(*auditFile, *auditMaxSize<<20, *auditMaxBackups)
+		if err != nil {
+			fatal("Opening audit log failed", "error", err)
+		}
+		gameServer.EnableAudit(audit)
+		slog.Info("Recording tool calls", "path", *auditFile)
+	}
+	// Stop every transport gracefully on SIGINT or SIGTERM; a second signal
+	// kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+		slog.Info("Shutting down")
+	}()
+
+	slog.Info("Starting MCP Tic-Tac-Toe server", "transports", transports.String())
+
+	err := gameServer.Serve(ctx, server.ServeOptions{
+		Transports:      transports,
+		Addr:            *addr,
+		RESTAddr:        *restAddr,
+		ShutdownTimeout: *shutdownTimeout,
+	})
+
+	// Deliver the last game events before exiting, dead-lettering whatever
+	// is still pending after the shutdown timeout
+	if dispatcher != nil {
+		closeCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		if err := dispatcher.Close(closeCtx); err != nil {
+			slog.Warn("Webhooks did not finish cleanly", "error", err)
+		}
+		cancel()
+	}
+	if audit != nil {
+		audit.Close()
+	}
 	if err != nil {
-		log.Fatalf("Server failed: %v", err)
-		os.Exit(1)
+		fatal("Server failed", "error", err)
 	}
+	slog.Info("Server stopped")
 }
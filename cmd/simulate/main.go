@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+func main() {
+	var a = flag.String("a", "perfect", "First strategy: "+strings.Join(game.StrategyNames(), ", "))
+	var b = flag.String("b", "random", "Second strategy")
+	var games = flag.Int("games", 100000, "Number of games to play")
+	var workers = flag.Int("workers", runtime.GOMAXPROCS(0), "Number of worker goroutines")
+	var seed = flag.Uint64("seed", uint64(time.Now().UnixNano()), "Random seed")
+	var alternate = flag.Bool("alternate", true, "Swap sides every game; otherwise the first strategy always plays X")
+	var asJSON = flag.Bool("json", false, "Print the result as JSON")
+	flag.Parse()
+
+	// Ctrl-C stops the run and reports the games played so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := game.Simulate(ctx, game.SimulationConfig{
+		Strategies: [2]string{*a, *b},
+		Games:      *games,
+		Workers:    *workers,
+		Seed:       *seed,
+		Alternate:  *alternate,
+	})
+	if result == nil {
+		log.Fatalf("Simulation failed: %v", err)
+	}
+	if err != nil {
+		log.Printf("Simulation interrupted: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatalf("Encoding result failed: %v", err)
+		}
+		return
+	}
+	fmt.Printf("%s vs %s, seed %d\n", *a, *b, *seed)
+	fmt.Println(result)
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Analysis is a structured description of a position's tactics
+type Analysis struct {
+	GameID         string                `json:"game_id"`
+	Status         GameStatus            `json:"status"`
+	Winner         Player                `json:"winner,omitempty"`
+	CurrentPlayer  Player                `json:"current_player,omitempty"` // Empty once the game is over
+	MoveCount      int                   `json:"move_count"`
+	AvailableMoves []Position            `json:"available_moves"`
+	Threats        map[Player][]Threat   `json:"threats"`              // Open two-in-a-rows for each side
+	ImmediateWins  []Position            `json:"immediate_wins"`       // Squares that win on the spot for the player to move
+	ForcedBlocks   []Position            `json:"forced_blocks"`        // Opponent winning squares the player to move must cover
+	Forks          []Player              `json:"forks"`                // Players who already have two distinct winning squares
+	ForkSquares    map[Player][]Position `json:"fork_squares"`         // Squares that would create a fork for each side
+	Evaluation     *Evaluation           `json:"evaluation,omitempty"` // Solved value for the player to move; nil once the game is over
+	Hash           PositionHash          `json:"hash"`                 // Symmetry-aware position hash
+	Canonical      string                `json:"canonical"`            // Canonical position in compact notation
+	Symmetry       Symmetry              `json:"symmetry"`             // Transformation mapping the board onto Canonical
+}
+
+// AnalyzePosition provides analysis of the current game position
+func (e *Engine) AnalyzePosition(gameID string) (*Analysis, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	game, exists := e.games[gameID]
+	if !exists {
+		return nil, fmt.Errorf("game with ID %s not found", gameID)
+	}
+
+	return AnalyzeGame(game), nil
+}
+
+// AnalyzeGame builds the analysis for a game. The game must not change while
+// it runs, so pass a Snapshot rather than a game another goroutine plays.
+func AnalyzeGame(game *GameState) *Analysis {
+	a := &Analysis{
+		GameID:         game.GameID,
+		Status:         game.Status,
+		Winner:         game.Winner,
+		MoveCount:      game.MoveCount,
+		AvailableMoves: []Position{},
+		Threats:        make(map[Player][]Threat),
+		ImmediateWins:  []Position{},
+		ForcedBlocks:   []Position{},
+		Forks:          []Player{},
+		ForkSquares:    make(map[Player][]Position),
+	}
+
+	canonical, symmetry := game.Board.Canonical()
+	a.Hash = game.Board.Hash()
+	a.Canonical = FormatFEN(canonical, game.CurrentPlayer)
+	a.Symmetry = symmetry
+
+	if game.IsGameOver() {
+		// A won game's CurrentPlayer is the winner, since the turn does not
+		// pass after the last move, so there is no player to move to evaluate
+		return a
+	}
+	a.CurrentPlayer = game.CurrentPlayer
+
+	board := game.Board
+	player, opponent := game.CurrentPlayer, game.CurrentPlayer.Opponent()
+
+	a.AvailableMoves = board.EmptyPositions()
+	for _, p := range []Player{PlayerX, PlayerO} {
+		a.Threats[p] = nonNil(board.Threats(p))
+		a.ForkSquares[p] = nonNil(board.ForkSquares(p))
+		if len(board.WinningSquares(p)) >= 2 {
+			a.Forks = append(a.Forks, p)
+		}
+	}
+	a.ImmediateWins = nonNil(board.WinningSquares(player))
+	a.ForcedBlocks = nonNil(board.WinningSquares(opponent))
+	evaluation := Evaluate(board, player)
+	a.Evaluation = &evaluation
+
+	return a
+}
+
+// nonNil replaces a nil slice with an empty one so it encodes as []
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// String summarizes the analysis in human-readable form
+func (a *Analysis) String() string {
+	switch a.Status {
+	case StatusWon:
+		return fmt.Sprintf("Game over: %s wins!", a.Winner)
+	case StatusDraw:
+		return "Game over: It's a draw!"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Current player: %s, Available moves: %d, Move count: %d",
+		a.CurrentPlayer, len(a.AvailableMoves), a.MoveCount)
+
+	opponent := a.CurrentPlayer.Opponent()
+	if len(a.ImmediateWins) > 0 {
+		fmt.Fprintf(&sb, "\nImmediate win for %s: %s", a.CurrentPlayer, joinPositions(a.ImmediateWins))
+	}
+	if len(a.ForcedBlocks) > 0 {
+		fmt.Fprintf(&sb, "\n%s must block: %s", a.CurrentPlayer, joinPositions(a.ForcedBlocks))
+	}
+	for _, p := range a.Forks {
+		fmt.Fprintf(&sb, "\n%s has a fork: two threats at once", p)
+	}
+	for _, p := range []Player{a.CurrentPlayer, opponent} {
+		if len(a.ForkSquares[p]) > 0 {
+			fmt.Fprintf(&sb, "\nFork squares for %s: %s", p, joinPositions(a.ForkSquares[p]))
+		}
+	}
+	fmt.Fprintf(&sb, "\nEvaluation for %s with perfect play: %s", a.CurrentPlayer, a.Evaluation)
+	fmt.Fprintf(&sb, "\nCanonical position: %s (%s, hash %s)", a.Canonical, a.Symmetry, a.Hash)
+
+	return sb.String()
+}
+
+// joinPositions formats positions as a comma-separated list
+func joinPositions(positions []Position) string {
+	names := make([]string, len(positions))
+	for i, pos := range positions {
+		names[i] = pos.String()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,118 @@
+package game
+
+import "math/bits"
+
+// bitboard is the engine's internal board representation: one 9-bit mask per
+// player, with bit row*3+col set when the player occupies that cell. Board
+// remains the public view; conversion in either direction is cheap.
+type bitboard struct {
+	x, o uint16
+}
+
+// fullMask has a bit set for every cell
+const fullMask uint16 = 1<<9 - 1
+
+// winMasks holds the cells of each winning line, derived from winLines
+var winMasks = func() [8]uint16 {
+	var masks [8]uint16
+	for i, line := range winLines {
+		for _, pos := range line {
+			masks[i] |= cellBit(pos)
+		}
+	}
+	return masks
+}()
+
+// cellBit returns the mask bit for a position
+func cellBit(pos Position) uint16 {
+	return 1 << (pos.Row*3 + pos.Col)
+}
+
+// cellPosition returns the position of a mask bit index
+func cellPosition(index int) Position {
+	return Position{Row: index / 3, Col: index % 3}
+}
+
+// bitboardOf converts a Board to its bitboard
+func bitboardOf(b *Board) bitboard {
+	var bb bitboard
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			switch b[row][col] {
+			case PlayerX:
+				bb.x |= 1 << (row*3 + col)
+			case PlayerO:
+				bb.o |= 1 << (row*3 + col)
+			}
+		}
+	}
+	return bb
+}
+
+// board converts the bitboard back to the public Board
+func (bb bitboard) board() Board {
+	var b Board
+	for i := 0; i < 9; i++ {
+		switch {
+		case bb.x&(1<<i) != 0:
+			b[i/3][i%3] = PlayerX
+		case bb.o&(1<<i) != 0:
+			b[i/3][i%3] = PlayerO
+		}
+	}
+	return b
+}
+
+// of returns the mask of cells occupied by the player
+func (bb bitboard) of(player Player) uint16 {
+	if player == PlayerX {
+		return bb.x
+	}
+	return bb.o
+}
+
+// empty returns the mask of unoccupied cells, i.e. the legal moves
+func (bb bitboard) empty() uint16 {
+	return fullMask &^ (bb.x | bb.o)
+}
+
+// full reports whether every cell is occupied
+func (bb bitboard) full() bool {
+	return bb.x|bb.o == fullMask
+}
+
+// play returns the bitboard with the player's mark added at bit index
+func (bb bitboard) play(index int, player Player) bitboard {
+	if player == PlayerX {
+		bb.x |= 1 << index
+	} else {
+		bb.o |= 1 << index
+	}
+	return bb
+}
+
+// count returns the number of occupied cells
+func (bb bitboard) count() int {
+	return bits.OnesCount16(bb.x | bb.o)
+}
+
+// hasWin reports whether the cells in mask contain a complete line
+func hasWin(mask uint16) bool {
+	for _, win := range winMasks {
+		if mask&win == win {
+			return true
+		}
+	}
+	return false
+}
+
+// positions lists the positions of the set bits in mask, in row-major order
+func positions(mask uint16) []Position {
+	result := make([]Position, 0, bits.OnesCount16(mask))
+	for mask != 0 {
+		index := bits.TrailingZeros16(mask)
+		result = append(result, cellPosition(index))
+		mask &= mask - 1
+	}
+	return result
+}
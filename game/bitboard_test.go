@@ -0,0 +1,165 @@
+package game
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"testing"
+)
+
+// reachableBoards returns every position reachable from the empty board
+func reachableBoards() []Board {
+	var boards []Board
+	seen := make(map[bitboard]bool)
+
+	var visit func(bb bitboard, toMove Player)
+	visit = func(bb bitboard, toMove Player) {
+		if seen[bb] {
+			return
+		}
+		seen[bb] = true
+		boards = append(boards, bb.board())
+		if bb.terminal(toMove) {
+			return
+		}
+		for empty := bb.empty(); empty != 0; empty &= empty - 1 {
+			visit(bb.play(bits.TrailingZeros16(empty), toMove), toMove.Opponent())
+		}
+	}
+	visit(bitboard{}, PlayerX)
+	return boards
+}
+
+func TestBitboardMatchesBoard(t *testing.T) {
+	engine := NewEngine()
+	for _, board := range reachableBoards() {
+		bb := bitboardOf(&board)
+		if bb.board() != board {
+			t.Fatalf("Round trip changed the board:\n%s", board.String())
+		}
+		for _, player := range []Player{PlayerX, PlayerO} {
+			if hasWin(bb.of(player)) != gridHasWin(&board, player) ||
+				gridHasWin(&board, player) != (len(board.CompletedLines(player)) > 0) {
+				t.Fatalf("Win check disagrees for %s on:\n%s", player, board.String())
+			}
+			if engine.checkWin(board, player) != hasWin(bb.of(player)) {
+				t.Fatalf("Engine win check disagrees for %s on:\n%s", player, board.String())
+			}
+		}
+		moves := positions(bb.empty())
+		empty := gridEmpty(&board)
+		if len(moves) != len(empty) || len(board.EmptyPositions()) != len(empty) || bb.full() != board.IsFull() {
+			t.Fatalf("Move generation disagrees on:\n%s", board.String())
+		}
+		for i := range moves {
+			if moves[i] != empty[i] {
+				t.Fatalf("Move order differs on:\n%s", board.String())
+			}
+		}
+	}
+}
+
+// gridHasWin is the grid baseline for the win check: it walks the cells of
+// every line on the Board
+func gridHasWin(board *Board, player Player) bool {
+	for _, line := range winLines {
+		if board[line[0].Row][line[0].Col] == player &&
+			board[line[1].Row][line[1].Col] == player &&
+			board[line[2].Row][line[2].Col] == player {
+			return true
+		}
+	}
+	return false
+}
+
+// gridEmpty is the grid baseline for move generation: it scans every cell
+func gridEmpty(board *Board) []Position {
+	var moves []Position
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			if board[row][col] == Empty {
+				moves = append(moves, Position{Row: row, Col: col})
+			}
+		}
+	}
+	return moves
+}
+
+func BenchmarkWinCheckBoard(b *testing.B) {
+	boards := reachableBoards()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gridHasWin(&boards[i%len(boards)], PlayerX)
+	}
+}
+
+func BenchmarkWinCheckBitboard(b *testing.B) {
+	boards := reachableBoards()
+	bbs := make([]bitboard, len(boards))
+	for i := range boards {
+		bbs[i] = bitboardOf(&boards[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = hasWin(bbs[i%len(bbs)].x)
+	}
+}
+
+func BenchmarkMoveGenerationBoard(b *testing.B) {
+	boards := reachableBoards()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gridEmpty(&boards[i%len(boards)])
+	}
+}
+
+func BenchmarkMoveGenerationBitboard(b *testing.B) {
+	boards := reachableBoards()
+	bbs := make([]bitboard, len(boards))
+	for i := range boards {
+		bbs[i] = bitboardOf(&boards[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for empty := bbs[i%len(bbs)].empty(); empty != 0; empty &= empty - 1 {
+			count += bits.TrailingZeros16(empty)
+		}
+		_ = count
+	}
+}
+
+// BenchmarkRandomPlayoutBitboard measures raw self-play throughput: complete
+// games of uniformly random moves on bitboards
+func BenchmarkRandomPlayoutBitboard(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var bb bitboard
+		toMove := PlayerX
+		for !bb.terminal(toMove) {
+			empty := bb.empty()
+			for skip := rng.IntN(bits.OnesCount16(empty)); skip > 0; skip-- {
+				empty &= empty - 1
+			}
+			bb = bb.play(bits.TrailingZeros16(empty), toMove)
+			toMove = toMove.Opponent()
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+}
+
+// BenchmarkEngineRandomGame measures complete random games played through the
+// Engine's public API
+func BenchmarkEngineRandomGame(b *testing.B) {
+	engine := NewEngine()
+	rng := rand.New(rand.NewPCG(1, 2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		game := engine.CreateGame("bench")
+		for !game.IsGameOver() {
+			moves, _ := engine.GetAvailableMoves("bench")
+			game, _ = engine.MakeMove("bench", moves[rng.IntN(len(moves))], game.CurrentPlayer)
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+}
@@ -0,0 +1,252 @@
+package game
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DatasetFormat is the file format of a training-data export
+type DatasetFormat string
+
+const (
+	FormatJSONL DatasetFormat = "jsonl" // One JSON object per line
+	FormatCSV   DatasetFormat = "csv"   // Header row, then one row per example
+)
+
+// MoveValue is the solver's value of one legal move, for the player making it
+type MoveValue struct {
+	Move  Position   `json:"move"`
+	Value Evaluation `json:"value"`
+}
+
+// Example is one training position: the board, its legal moves and their
+// solved values, and how the game actually ended
+type Example struct {
+	FEN        string       `json:"fen"`
+	ToMove     Player       `json:"to_move"`
+	Ply        int          `json:"ply"` // Moves already played
+	Hash       PositionHash `json:"hash"`
+	LegalMoves []Position   `json:"legal_moves"`
+	MoveValues []MoveValue  `json:"move_values"`
+	BestMoves  []Position   `json:"best_moves"`
+	Value      Evaluation   `json:"value"` // Solved value for the side to move
+	// Outcome is the final result for the side to move: the actual result of
+	// the game for self-play samples, the perfect-play result for enumeration
+	Outcome Outcome `json:"outcome"`
+	Game    int     `json:"game,omitempty"` // 1-based self-play game number
+}
+
+// newExample evaluates a position with the solver. Outcome and Game are left
+// for the caller.
+func newExample(solver *Solver, board Board, toMove Player) Example {
+	moves := solver.EvaluateMoves(board, toMove)
+	example := Example{
+		FEN:        FormatFEN(board, toMove),
+		ToMove:     toMove,
+		Ply:        board.Count(PlayerX) + board.Count(PlayerO),
+		Hash:       board.Hash(),
+		LegalMoves: make([]Position, 0, len(moves)),
+		MoveValues: make([]MoveValue, 0, len(moves)),
+		BestMoves:  solver.BestMoves(board, toMove),
+		Value:      solver.Evaluate(board, toMove),
+	}
+	for _, move := range moves {
+		example.LegalMoves = append(example.LegalMoves, move.Position)
+		example.MoveValues = append(example.MoveValues, MoveValue{Move: move.Position, Value: move.Evaluation})
+	}
+	return example
+}
+
+// EnumerateExamples calls emit for every reachable, undecided position, in
+// order of ply and then board encoding. With canonical set, only one
+// position per symmetry class is emitted. Outcome is the perfect-play result.
+func EnumerateExamples(canonical bool, emit func(Example) error) error {
+	var boards []bitboard
+	seen := make(map[bitboard]bool)
+	var visit func(bb bitboard, toMove Player)
+	visit = func(bb bitboard, toMove Player) {
+		if seen[bb] {
+			return
+		}
+		seen[bb] = true
+		if bb.terminal(toMove) {
+			return
+		}
+		boards = append(boards, bb)
+		for empty := bb.empty(); empty != 0; empty &= empty - 1 {
+			visit(bb.play(bits.TrailingZeros16(empty), toMove), toMove.Opponent())
+		}
+	}
+	visit(bitboard{}, PlayerX)
+
+	sort.Slice(boards, func(i, j int) bool {
+		if ci, cj := boards[i].count(), boards[j].count(); ci != cj {
+			return ci < cj
+		}
+		return keyOf(boards[i].board()) < keyOf(boards[j].board())
+	})
+
+	classes := make(map[PositionHash]bool)
+	for _, bb := range boards {
+		board := bb.board()
+		if canonical {
+			hash := board.Hash()
+			if classes[hash] {
+				continue
+			}
+			classes[hash] = true
+		}
+		toMove := PlayerX
+		if bb.count()%2 == 1 {
+			toMove = PlayerO
+		}
+		example := newExample(defaultSolver, board, toMove)
+		example.Outcome = example.Value.Outcome
+		if err := emit(example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SampleExamples plays games between two named strategies, alternating who
+// plays X, and calls emit for every position reached before the game ended.
+// Outcome is the actual result of that game for the side to move.
+func SampleExamples(ctx context.Context, strategies [2]string, games int, seed uint64, emit func(Example) error) error {
+	if games <= 0 {
+		return fmt.Errorf("games must be positive")
+	}
+	var contestants [2]Strategy
+	for i, name := range strategies {
+		strategy, err := NewStrategy(name)
+		if err != nil {
+			return err
+		}
+		contestants[i] = strategy
+	}
+	rng := rand.New(rand.NewPCG(seed, 0))
+
+	for game := 1; game <= games; game++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		players := map[Player]Strategy{PlayerX: contestants[0], PlayerO: contestants[1]}
+		if game%2 == 0 {
+			players[PlayerX], players[PlayerO] = contestants[1], contestants[0]
+		}
+
+		var examples []Example
+		board, toMove, winner := NewBoard(), PlayerX, Empty
+		for !board.IsFull() {
+			example := newExample(defaultSolver, board, toMove)
+			example.Game = game
+			examples = append(examples, example)
+
+			board.Set(players[toMove].ChooseMove(board, toMove, rng), toMove)
+			if len(board.CompletedLines(toMove)) > 0 {
+				winner = toMove
+				break
+			}
+			toMove = toMove.Opponent()
+		}
+
+		for _, example := range examples {
+			switch winner {
+			case Empty:
+				example.Outcome = OutcomeDraw
+			case example.ToMove:
+				example.Outcome = OutcomeWin
+			default:
+				example.Outcome = OutcomeLoss
+			}
+			if err := emit(example); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// csvHeader lists the CSV columns. The v_ columns hold the signed solver
+// score of playing that square for the side to move (positive wins, faster
+// wins higher; negative losses; 0 draws), empty when the square is taken.
+var csvHeader = []string{
+	"fen", "to_move", "ply", "hash", "legal_moves", "best_moves", "value", "value_plies", "outcome", "game",
+	"v_A1", "v_B1", "v_C1", "v_A2", "v_B2", "v_C2", "v_A3", "v_B3", "v_C3",
+}
+
+// DatasetWriter writes examples in JSONL or CSV
+type DatasetWriter struct {
+	format  DatasetFormat
+	json    *json.Encoder
+	csv     *csv.Writer
+	started bool
+}
+
+// NewDatasetWriter creates a writer for the given format
+func NewDatasetWriter(w io.Writer, format DatasetFormat) (*DatasetWriter, error) {
+	switch format {
+	case FormatJSONL:
+		return &DatasetWriter{format: format, json: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		return &DatasetWriter{format: format, csv: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown dataset format %q (supported: jsonl, csv)", format)
+}
+
+// Write writes one example
+func (d *DatasetWriter) Write(example Example) error {
+	if d.format == FormatJSONL {
+		return d.json.Encode(example)
+	}
+
+	if !d.started {
+		d.started = true
+		if err := d.csv.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	row := []string{
+		example.FEN,
+		string(example.ToMove),
+		strconv.Itoa(example.Ply),
+		example.Hash.String(),
+		joinMoves(example.LegalMoves),
+		joinMoves(example.BestMoves),
+		example.Value.Outcome.String(),
+		strconv.Itoa(example.Value.Plies),
+		example.Outcome.String(),
+		strconv.Itoa(example.Game),
+	}
+	values := make([]string, 9)
+	for _, mv := range example.MoveValues {
+		values[mv.Move.Row*3+mv.Move.Col] = strconv.Itoa(mv.Value.score())
+	}
+	return d.csv.Write(append(row, values...))
+}
+
+// Flush writes any buffered data to the underlying writer
+func (d *DatasetWriter) Flush() error {
+	if d.csv != nil {
+		d.csv.Flush()
+		return d.csv.Error()
+	}
+	return nil
+}
+
+// joinMoves formats positions separated by spaces, e.g. "A1 B2"
+func joinMoves(positions []Position) string {
+	names := make([]string, len(positions))
+	for i, pos := range positions {
+		names[i] = pos.String()
+	}
+	return strings.Join(names, " ")
+}
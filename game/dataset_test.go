@@ -0,0 +1,113 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEnumerateExamples(t *testing.T) {
+	var all, canonical []Example
+	collect := func(into *[]Example) func(Example) error {
+		return func(e Example) error {
+			*into = append(*into, e)
+			return nil
+		}
+	}
+	if err := EnumerateExamples(false, collect(&all)); err != nil {
+		t.Fatalf("EnumerateExamples() failed: %v", err)
+	}
+	if err := EnumerateExamples(true, collect(&canonical)); err != nil {
+		t.Fatalf("EnumerateExamples() failed: %v", err)
+	}
+
+	// 5478 reachable positions, of which 958 are decided; 765 classes, 138 decided
+	if len(all) != 4520 || len(canonical) != 627 {
+		t.Fatalf("Expected 4520 and 627 positions, got %d and %d", len(all), len(canonical))
+	}
+
+	first := all[0]
+	if first.FEN != StartFEN || first.Ply != 0 || len(first.LegalMoves) != 9 || first.Outcome != OutcomeDraw {
+		t.Errorf("Unexpected first example: %+v", first)
+	}
+	for _, e := range all {
+		if len(e.MoveValues) != len(e.LegalMoves) || len(e.BestMoves) == 0 {
+			t.Fatalf("Incomplete example %+v", e)
+		}
+		if e.Outcome != e.Value.Outcome {
+			t.Fatalf("Enumerated outcome should match the solved value: %+v", e)
+		}
+	}
+}
+
+func TestSampleExamples(t *testing.T) {
+	var examples []Example
+	err := SampleExamples(context.Background(), [2]string{"perfect", "random"}, 10, 5, func(e Example) error {
+		examples = append(examples, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SampleExamples() failed: %v", err)
+	}
+
+	games := make(map[int]int)
+	for _, e := range examples {
+		games[e.Game]++
+		// Perfect play never loses, so whoever is winning can't end up lost
+		if e.Value.Outcome == OutcomeWin && e.Outcome == OutcomeLoss {
+			t.Errorf("A won position was lost: %+v", e)
+		}
+	}
+	if len(games) != 10 || examples[0].Game != 1 || examples[0].Ply != 0 {
+		t.Errorf("Expected positions from 10 games starting at the empty board, got %v", games)
+	}
+
+	if err := SampleExamples(context.Background(), [2]string{"perfect", "nope"}, 1, 0, nil); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
+
+func TestDatasetWriter(t *testing.T) {
+	board, toMove, _ := ParseFEN("XX./OO./... x")
+	example := newExample(defaultSolver, board, toMove)
+	example.Outcome = OutcomeWin
+
+	var buf bytes.Buffer
+	writer, err := NewDatasetWriter(&buf, FormatJSONL)
+	if err != nil {
+		t.Fatalf("NewDatasetWriter() failed: %v", err)
+	}
+	writer.Write(example)
+	writer.Write(example)
+	writer.Flush()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var decoded map[string]any
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &decoded) != nil {
+		t.Fatalf("Expected two JSON lines, got %q", buf.String())
+	}
+	if decoded["fen"] != "XX./OO./... x" || decoded["outcome"] != "win" {
+		t.Errorf("Unexpected JSON example: %v", decoded)
+	}
+
+	buf.Reset()
+	writer, _ = NewDatasetWriter(&buf, FormatCSV)
+	writer.Write(example)
+	writer.Flush()
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 2 || len(rows[1]) != len(csvHeader) {
+		t.Fatalf("Expected a header and one row, got %v (%v)", rows, err)
+	}
+	row := rows[1]
+	if row[5] != "C1" || row[6] != "win" || row[12] != "9" || row[10] != "" {
+		t.Errorf("Unexpected CSV row: %v", row)
+	}
+
+	if _, err := NewDatasetWriter(&buf, "parquet"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -1,35 +1,132 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 )
 
-// Engine manages the game logic and state
+// Engine manages the game logic and state. Games it returns are copies
+// taken under its lock, so callers may read them while play continues.
 type Engine struct {
-	games map[string]*GameState
-	mutex sync.RWMutex
+	games     map[string]*GameState
+	mutex     sync.RWMutex
+	events    *EventBus
+	observers engineObservers
 }
 
 // NewEngine creates a new game engine
 func NewEngine() *Engine {
 	return &Engine{
-		games: make(map[string]*GameState),
+		games:  make(map[string]*GameState),
+		events: NewEventBus(),
 	}
 }
 
+// ErrGameExists is wrapped by the errors for game IDs that are already taken
+var ErrGameExists = errors.New("already exists")
+
+// GameOptions configures a game created with CreateGameWithOptions
+type GameOptions struct {
+	Variant Variant
+	Players map[Player]string
+	FEN     string  // Optional starting position in compact notation
+	Puzzle  *Puzzle // Puzzle the game was created for, if any
+	Owner   string  // Authenticated principal creating the game, if any
+}
+
 // CreateGame creates a new game with the given ID
 func (e *Engine) CreateGame(gameID string) *GameState {
+	game, _ := e.CreateGameWithOptions(gameID, GameOptions{})
+	return game
+}
+
+// CreateGameWithOptions creates a new game with the given ID and options,
+// replacing any game with the same ID. It fails if the starting position is
+// invalid, or if opts.Owner is set and the existing game is not theirs.
+func (e *Engine) CreateGameWithOptions(gameID string, opts GameOptions) (*GameState, error) {
+	return e.createGame(gameID, opts, true)
+}
+
+// CreateGameIfAbsent is like CreateGameWithOptions but fails with an error
+// wrapping ErrGameExists if the ID is already taken
+func (e *Engine) CreateGameIfAbsent(gameID string, opts GameOptions) (*GameState, error) {
+	return e.createGame(gameID, opts, false)
+}
+
+// createGame creates a game, checking the existing one with the same ID
+// under the same lock that stores the new one
+func (e *Engine) createGame(gameID string, opts GameOptions, replace bool) (*GameState, error) {
+	game := NewGame(gameID)
+	if opts.FEN != "" {
+		if err := e.setupPosition(game, opts.FEN); err != nil {
+			return nil, err
+		}
+		game.Variant = VariantSetup
+	}
+	if opts.Variant != "" {
+		game.Variant = opts.Variant
+	}
+	for player, name := range opts.Players {
+		if name != "" {
+			game.Players[player] = name
+		}
+	}
+	game.Puzzle = opts.Puzzle
+	game.Owner = opts.Owner
+
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	game := NewGame(gameID)
+	if existing, exists := e.games[gameID]; exists {
+		if !replace {
+			return nil, fmt.Errorf("game with ID %s %w", gameID, ErrGameExists)
+		}
+		if err := existing.CheckOwner(opts.Owner); err != nil {
+			return nil, err
+		}
+		e.publish(EventGameDeleted, existing, nil)
+	}
 	e.games[gameID] = game
-	return game
+	e.publish(EventGameCreated, game, nil)
+	return game.Clone(), nil
 }
 
-// GetGame retrieves a game by ID
+// setupPosition places a game at the position described by fen, recording it
+// as the game's starting position
+func (e *Engine) setupPosition(game *GameState, fen string) error {
+	board, toMove, err := ParseFEN(fen)
+	if err != nil {
+		return fmt.Errorf("invalid position: %v", err)
+	}
+
+	game.Board = board
+	game.CurrentPlayer = toMove
+	game.Status = StatusOngoing
+	game.Winner = Empty
+	game.StartFEN = FormatFEN(board, toMove)
+
+	// ParseFEN guarantees at most one player has a completed line, and that
+	// the side to move is the player who did not just complete it
+	last := game.NextPlayer()
+	if e.checkWin(board, last) {
+		game.Status = StatusWon
+		game.Winner = last
+	} else if board.IsFull() {
+		game.Status = StatusDraw
+	}
+	return nil
+}
+
+// GetGame retrieves a copy of a game by ID; it is the same as Snapshot
 func (e *Engine) GetGame(gameID string) (*GameState, error) {
+	return e.Snapshot(gameID)
+}
+
+// Snapshot returns a copy of a game that is safe to read while other
+// goroutines keep playing it
+func (e *Engine) Snapshot(gameID string) (*GameState, error) {
 	e.mutex.RLock()
 	defer e.mutex.RUnlock()
 
@@ -37,11 +134,25 @@ func (e *Engine) GetGame(gameID string) (*GameState, error) {
 	if !exists {
 		return nil, fmt.Errorf("game with ID %s not found", gameID)
 	}
-	return game, nil
+	return game.Clone(), nil
 }
 
 // MakeMove attempts to make a move on the board
 func (e *Engine) MakeMove(gameID string, pos Position, player Player) (*GameState, error) {
+	return e.MakeMoveAs(gameID, pos, player, "")
+}
+
+// MakeMoveAs plays a move for an authenticated principal. The mark must be
+// free or already bound to them, and it is bound to them if the move is
+// legal. An empty principal behaves like MakeMove.
+func (e *Engine) MakeMoveAs(gameID string, pos Position, player Player, principal string) (*GameState, error) {
+	start := time.Now()
+	game, err := e.makeMove(gameID, pos, player, principal)
+	e.observeMove(start, err)
+	return game, err
+}
+
+func (e *Engine) makeMove(gameID string, pos Position, player Player, principal string) (*GameState, error) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
@@ -50,27 +161,44 @@ func (e *Engine) MakeMove(gameID string, pos Position, player Player) (*GameStat
 		return nil, fmt.Errorf("game with ID %s not found", gameID)
 	}
 
+	if err := checkSeat(game, player, principal); err != nil {
+		return nil, err
+	}
+	if err := e.applyMove(game, pos, player); err != nil {
+		return nil, err
+	}
+	bindSeat(game, player, principal)
+	e.publishMove(game)
+
+	return game.Clone(), nil
+}
+
+// applyMove validates and plays a move, updating the game's status
+func (e *Engine) applyMove(game *GameState, pos Position, player Player) error {
 	// Validate the move
 	if err := e.validateMove(game, pos, player); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Make the move
 	game.Board.Set(pos, player)
 	game.MoveCount++
+	game.Moves = append(game.Moves, Move{Player: player, Position: pos})
+	game.UpdatedAt = time.Now()
 
 	// Check for win condition
-	if e.checkWin(game.Board, player) {
+	bb := bitboardOf(&game.Board)
+	if hasWin(bb.of(player)) {
 		game.Status = StatusWon
 		game.Winner = player
-	} else if game.Board.IsFull() {
+	} else if bb.full() {
 		game.Status = StatusDraw
 	} else {
 		// Switch to next player
 		game.CurrentPlayer = game.NextPlayer()
 	}
 
-	return game, nil
+	return nil
 }
 
 // validateMove checks if a move is valid
@@ -100,29 +228,7 @@ func (e *Engine) validateMove(game *GameState, pos Position, player Player) erro
 
 // checkWin checks if the given player has won
 func (e *Engine) checkWin(board Board, player Player) bool {
-	// Check rows
-	for row := 0; row < 3; row++ {
-		if board[row][0] == player && board[row][1] == player && board[row][2] == player {
-			return true
-		}
-	}
-
-	// Check columns
-	for col := 0; col < 3; col++ {
-		if board[0][col] == player && board[1][col] == player && board[2][col] == player {
-			return true
-		}
-	}
-
-	// Check diagonals
-	if board[0][0] == player && board[1][1] == player && board[2][2] == player {
-		return true
-	}
-	if board[0][2] == player && board[1][1] == player && board[2][0] == player {
-		return true
-	}
-
-	return false
+	return hasWin(bitboardOf(&board).of(player))
 }
 
 // ResetGame resets an existing game to initial state
@@ -140,9 +246,20 @@ func (e *Engine) ResetGame(gameID string) (*GameState, error) {
 	game.CurrentPlayer = PlayerX
 	game.Status = StatusOngoing
 	game.Winner = Empty
+	if game.StartFEN != "" {
+		if err := e.setupPosition(game, game.StartFEN); err != nil {
+			return nil, err
+		}
+	}
 	game.MoveCount = 0
-
-	return game, nil
+	game.Moves = nil
+	game.PuzzleAttempts = 0
+	game.PuzzleSolved = false
+	game.HintsUsed = 0
+	game.UpdatedAt = time.Now()
+	e.publish(EventGameReset, game, nil)
+
+	return game.Clone(), nil
 }
 
 // DeleteGame removes a game from the engine
@@ -150,11 +267,13 @@ func (e *Engine) DeleteGame(gameID string) error {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	if _, exists := e.games[gameID]; !exists {
+	game, exists := e.games[gameID]
+	if !exists {
 		return fmt.Errorf("game with ID %s not found", gameID)
 	}
 
 	delete(e.games, gameID)
+	e.publish(EventGameDeleted, game, nil)
 	return nil
 }
 
@@ -184,39 +303,5 @@ func (e *Engine) GetAvailableMoves(gameID string) ([]Position, error) {
 		return []Position{}, nil
 	}
 
-	var moves []Position
-	for row := 0; row < 3; row++ {
-		for col := 0; col < 3; col++ {
-			pos := Position{Row: row, Col: col}
-			if game.Board.IsEmpty(pos) {
-				moves = append(moves, pos)
-			}
-		}
-	}
-
-	return moves, nil
-}
-
-// AnalyzePosition provides analysis of the current game position
-func (e *Engine) AnalyzePosition(gameID string) (string, error) {
-	e.mutex.RLock()
-	defer e.mutex.RUnlock()
-
-	game, exists := e.games[gameID]
-	if !exists {
-		return "", fmt.Errorf("game with ID %s not found", gameID)
-	}
-
-	if game.IsGameOver() {
-		switch game.Status {
-		case StatusWon:
-			return fmt.Sprintf("Game over: %s wins!", game.Winner), nil
-		case StatusDraw:
-			return "Game over: It's a draw!", nil
-		}
-	}
-
-	availableMoves, _ := e.GetAvailableMoves(gameID)
-	return fmt.Sprintf("Current player: %s, Available moves: %d, Move count: %d",
-		game.CurrentPlayer, len(availableMoves), game.MoveCount), nil
+	return positions(bitboardOf(&game.Board).empty()), nil
 }
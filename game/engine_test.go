@@ -1,7 +1,9 @@
 package game
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestNewEngine(t *testing.T) {
@@ -32,6 +34,30 @@ func TestCreateGame(t *testing.T) {
 	}
 }
 
+func TestCreateGameIfAbsent(t *testing.T) {
+	engine := NewEngine()
+	if _, err := engine.CreateGameIfAbsent("only", GameOptions{Owner: "alice"}); err != nil {
+		t.Fatalf("CreateGameIfAbsent failed: %v", err)
+	}
+	playMoves(t, engine, "only", "B2")
+
+	_, err := engine.CreateGameIfAbsent("only", GameOptions{})
+	if !errors.Is(err, ErrGameExists) || err.Error() != "game with ID only already exists" {
+		t.Errorf("Expected ErrGameExists, got %v", err)
+	}
+	if game, _ := engine.Snapshot("only"); len(game.Moves) != 1 {
+		t.Error("The existing game should be left alone")
+	}
+
+	// Replacing checks the owner under the same lock
+	if _, err := engine.CreateGameWithOptions("only", GameOptions{Owner: "bob"}); err == nil {
+		t.Error("Expected bob to be refused alice's game")
+	}
+	if _, err := engine.CreateGameWithOptions("only", GameOptions{Owner: "alice"}); err != nil {
+		t.Errorf("Expected alice to replace her own game, got %v", err)
+	}
+}
+
 func TestMakeMove(t *testing.T) {
 	engine := NewEngine()
 	engine.CreateGame("test-game")
@@ -189,3 +215,136 @@ func TestPositionParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryGames(t *testing.T) {
+	engine := NewEngine()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, id := range []string{"g1", "g2", "g3", "g4", "g5"} {
+		engine.CreateGameWithOptions(id, GameOptions{
+			Players: map[Player]string{PlayerX: "alice", PlayerO: "bot"},
+		})
+		game := engine.games[id]
+		game.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		game.UpdatedAt = game.CreatedAt
+	}
+	engine.CreateGameWithOptions("other", GameOptions{Players: map[Player]string{PlayerX: "carol"}})
+	engine.games["other"].CreatedAt = base
+
+	pos, _ := ParsePosition("B2")
+	engine.MakeMove("g2", pos, PlayerX)
+
+	// Filter by player, newest first
+	page, err := engine.QueryGames(GameFilter{Player: "ALICE", Limit: 2})
+	if err != nil {
+		t.Fatalf("QueryGames() failed: %v", err)
+	}
+	if page.Total != 5 {
+		t.Errorf("Expected 5 matching games, got %d", page.Total)
+	}
+	if len(page.Games) != 2 || page.Games[0].GameID != "g5" || page.Games[1].GameID != "g4" {
+		t.Fatalf("Unexpected first page: %+v", page.Games)
+	}
+	if page.NextCursor == "" {
+		t.Fatal("Expected a next cursor")
+	}
+
+	// Follow cursors until exhausted
+	seen := []string{"g5", "g4"}
+	for page.NextCursor != "" {
+		page, err = engine.QueryGames(GameFilter{Player: "alice", Limit: 2, Cursor: page.NextCursor})
+		if err != nil {
+			t.Fatalf("QueryGames() with cursor failed: %v", err)
+		}
+		for _, summary := range page.Games {
+			seen = append(seen, summary.GameID)
+		}
+	}
+	expected := []string{"g5", "g4", "g3", "g2", "g1"}
+	if len(seen) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, seen)
+		}
+	}
+
+	// Sort by last update
+	page, _ = engine.QueryGames(GameFilter{SortBy: SortByUpdated, Limit: 1})
+	if page.Games[0].GameID != "g2" || page.Games[0].MoveCount != 1 {
+		t.Errorf("Expected most recently updated game g2, got %+v", page.Games[0])
+	}
+
+	// Created-after and ascending order
+	page, _ = engine.QueryGames(GameFilter{CreatedAfter: base.Add(2 * time.Minute), Ascending: true})
+	if page.Total != 2 || page.Games[0].GameID != "g4" {
+		t.Errorf("Expected g4 and g5, got %+v", page.Games)
+	}
+
+	// Status filter
+	page, _ = engine.QueryGames(GameFilter{Status: StatusWon})
+	if page.Total != 0 {
+		t.Errorf("Expected no won games, got %d", page.Total)
+	}
+
+	if _, err := engine.QueryGames(GameFilter{Cursor: "not-a-cursor!"}); err == nil {
+		t.Error("Should reject an invalid cursor")
+	}
+}
+
+func TestAnalyzePosition(t *testing.T) {
+	engine := NewEngine()
+
+	// X threatens row 1 and column C at once; O to move
+	engine.CreateGameWithOptions("fork", GameOptions{FEN: "X.X/.O./O.X o"})
+
+	analysis, err := engine.AnalyzePosition("fork")
+	if err != nil {
+		t.Fatalf("AnalyzePosition() failed: %v", err)
+	}
+	if len(analysis.ImmediateWins) != 0 {
+		t.Errorf("O should have no immediate win, got %v", analysis.ImmediateWins)
+	}
+	if len(analysis.ForcedBlocks) != 2 {
+		t.Errorf("O should face two blocks, got %v", analysis.ForcedBlocks)
+	}
+	if len(analysis.Forks) != 1 || analysis.Forks[0] != PlayerX {
+		t.Errorf("X should have a fork, got %v", analysis.Forks)
+	}
+	if analysis.Evaluation.Outcome != OutcomeLoss {
+		t.Errorf("O should be lost, got %s", analysis.Evaluation)
+	}
+
+	// X to move can set up a fork at A3 or C1
+	engine.CreateGameWithOptions("fork-squares", GameOptions{FEN: "X../.O./..X o"})
+	pos, _ := ParsePosition("A3")
+	engine.MakeMove("fork-squares", pos, PlayerO)
+	analysis, _ = engine.AnalyzePosition("fork-squares")
+	if len(analysis.ForcedBlocks) != 1 || analysis.ForcedBlocks[0].String() != "C1" {
+		t.Errorf("X should have to block C1, got %v", analysis.ForcedBlocks)
+	}
+	if len(analysis.ForkSquares[PlayerX]) == 0 {
+		t.Error("X should have fork squares")
+	}
+
+	// Mirrored positions share a hash and canonical form
+	engine.CreateGameWithOptions("mirror", GameOptions{FEN: "..X/.O./X.. o"})
+	engine.CreateGameWithOptions("original", GameOptions{FEN: "X../.O./..X o"})
+	mirror, _ := engine.AnalyzePosition("mirror")
+	original, _ := engine.AnalyzePosition("original")
+	if mirror.Hash != original.Hash || mirror.Canonical != original.Canonical {
+		t.Errorf("Mirrored positions should share a hash, got %s and %s", mirror.Hash, original.Hash)
+	}
+
+	// A finished game has no player to move and nothing to evaluate
+	engine.CreateGameWithOptions("won", GameOptions{FEN: "XXX/OO./... o"})
+	won, _ := engine.AnalyzePosition("won")
+	if won.Winner != PlayerX || won.CurrentPlayer != Empty || won.Evaluation != nil {
+		t.Errorf("Expected only the winner for a finished game, got %+v", won)
+	}
+
+	if _, err := engine.AnalyzePosition("missing"); err == nil {
+		t.Error("Should fail for a missing game")
+	}
+}
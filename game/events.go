@@ -0,0 +1,152 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies what happened to a game
+type EventType string
+
+const (
+	EventGameCreated EventType = "game_created" // A game was created or imported
+	EventMoveMade    EventType = "move_made"    // A move was played
+	EventGameEnded   EventType = "game_ended"   // The game was won or drawn
+	EventGameReset   EventType = "game_reset"   // The game was reset to its start
+	EventGameDeleted EventType = "game_deleted" // The game was removed
+)
+
+// Event describes a change to a game. Game is a snapshot of the game right
+// after the change, owned by the event, so subscribers may keep it.
+type Event struct {
+	Type     EventType  `json:"type"`
+	Sequence uint64     `json:"sequence"` // Increases by one per event published on a bus
+	Time     time.Time  `json:"time"`
+	GameID   string     `json:"game_id"`
+	Game     *GameState `json:"game"`
+	Move     *Move      `json:"move,omitempty"` // The move played, for MoveMade and GameEnded by a move
+}
+
+// EventHandler receives published events
+type EventHandler func(Event)
+
+// EventBus delivers events to subscribers synchronously, in publication
+// order. Handlers run on the publisher's goroutine; the Engine publishes
+// while holding its lock, so handlers must return quickly and must not call
+// back into the Engine. Subscribers with slow work should hand events off to
+// their own goroutine.
+type EventBus struct {
+	mutex       sync.Mutex
+	sequence    uint64
+	nextID      int
+	subscribers map[int]EventHandler
+	order       []int
+}
+
+// NewEventBus creates a bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]EventHandler)}
+}
+
+// Subscribe registers a handler for every subsequent event. The returned
+// function removes it; calling it more than once is harmless.
+func (b *EventBus) Subscribe(handler EventHandler) (unsubscribe func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = handler
+	b.order = append(b.order, id)
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		if _, ok := b.subscribers[id]; !ok {
+			return
+		}
+		delete(b.subscribers, id)
+		for i, other := range b.order {
+			if other == id {
+				b.order = append(b.order[:i:i], b.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// idle reports whether the bus has no subscribers
+func (b *EventBus) idle() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.order) == 0
+}
+
+// Publish stamps the event with the next sequence number and, if unset, the
+// current time, then calls every subscriber in subscription order
+func (b *EventBus) Publish(event Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.sequence++
+	event.Sequence = b.sequence
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	for _, id := range b.order {
+		b.subscribers[id](event)
+	}
+}
+
+// Clone returns a deep copy of the game state
+func (g *GameState) Clone() *GameState {
+	clone := *g
+	clone.Moves = append([]Move(nil), g.Moves...)
+	clone.Players = make(map[Player]string, len(g.Players))
+	for player, name := range g.Players {
+		clone.Players[player] = name
+	}
+	if g.Seats != nil {
+		clone.Seats = make(map[Player]string, len(g.Seats))
+		for player, principal := range g.Seats {
+			clone.Seats[player] = principal
+		}
+	}
+	if g.Puzzle != nil {
+		puzzle := *g.Puzzle
+		puzzle.Solutions = append([]Position(nil), g.Puzzle.Solutions...)
+		clone.Puzzle = &puzzle
+	}
+	return &clone
+}
+
+// Events returns the engine's event bus
+func (e *Engine) Events() *EventBus {
+	return e.events
+}
+
+// publish sends an event for the game. Callers hold e.mutex so that events
+// are published in the order the changes were made. With no subscribers
+// the event is dropped without cloning the game.
+func (e *Engine) publish(eventType EventType, game *GameState, move *Move) {
+	if e.events.idle() {
+		return
+	}
+	e.events.Publish(Event{
+		Type:   eventType,
+		GameID: game.GameID,
+		Game:   game.Clone(),
+		Move:   move,
+	})
+}
+
+// publishMove sends MoveMade for the game's last move, then GameEnded if
+// that move finished the game
+func (e *Engine) publishMove(game *GameState) {
+	move := game.Moves[len(game.Moves)-1]
+	e.publish(EventMoveMade, game, &move)
+	if game.IsGameOver() {
+		e.publish(EventGameEnded, game, &move)
+	}
+}
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"sync"
+	"testing"
+)
+
+// recordEvents subscribes to the engine and collects every event
+func recordEvents(engine *Engine) *[]Event {
+	var events []Event
+	engine.Events().Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+	return &events
+}
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestEngineEvents(t *testing.T) {
+	engine := NewEngine()
+	events := recordEvents(engine)
+
+	engine.CreateGame("evented")
+	playMoves(t, engine, "evented", "B2", "A1", "C2", "A2", "C1", "A3")
+	engine.ResetGame("evented")
+	engine.DeleteGame("evented")
+
+	want := []EventType{
+		EventGameCreated,
+		EventMoveMade, EventMoveMade, EventMoveMade, EventMoveMade, EventMoveMade, EventMoveMade, EventGameEnded,
+		EventGameReset, EventGameDeleted,
+	}
+	got := eventTypes(*events)
+	if len(got) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected events %v, got %v", want, got)
+		}
+	}
+
+	for i, e := range *events {
+		if e.Sequence != uint64(i+1) || e.GameID != "evented" || e.Game == nil || e.Time.IsZero() {
+			t.Errorf("Unexpected event %d: %+v", i, e)
+		}
+	}
+
+	first := (*events)[1]
+	if first.Move == nil || first.Move.Position.String() != "B2" || first.Game.MoveCount != 1 {
+		t.Errorf("MoveMade should carry the move and the game after it, got %+v", first)
+	}
+	ended := (*events)[7]
+	if ended.Game.Winner != PlayerO || ended.Move == nil || ended.Move.Position.String() != "A3" {
+		t.Errorf("GameEnded should carry the winning move, got %+v", ended)
+	}
+
+	// Snapshots are independent of the live game and of each other
+	if len(first.Game.Moves) != 1 || (*events)[8].Game.MoveCount != 0 {
+		t.Errorf("Event snapshots were modified by later changes")
+	}
+}
+
+func TestEngineEventsOnReplace(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGame("replaced")
+	playMoves(t, engine, "replaced", "B2")
+	events := recordEvents(engine)
+
+	engine.CreateGame("replaced")
+
+	got := eventTypes(*events)
+	if len(got) != 2 || got[0] != EventGameDeleted || got[1] != EventGameCreated {
+		t.Fatalf("Expected [%s %s], got %v", EventGameDeleted, EventGameCreated, got)
+	}
+	if old := (*events)[0].Game; old.MoveCount != 1 {
+		t.Errorf("GameDeleted should carry the replaced game, got %+v", old)
+	}
+	if fresh := (*events)[1].Game; fresh.MoveCount != 0 {
+		t.Errorf("GameCreated should carry the new game, got %+v", fresh)
+	}
+}
+
+func TestEngineEventsFromImportAndPuzzles(t *testing.T) {
+	engine := NewEngine()
+	events := recordEvents(engine)
+
+	record, _ := ParseRecord("1. B2 A1 2. C2 A2 3. C1 A3 0-1")
+	engine.ImportGame("imported", record)
+
+	puzzle := easiestPuzzle(t, PuzzleWinInN)
+	engine.CreatePuzzleGame("puzzle", puzzle)
+	engine.CheckPuzzleAnswer("puzzle", puzzle.Solutions[0])
+
+	got := eventTypes(*events)
+	want := []EventType{EventGameCreated, EventGameCreated, EventMoveMade}
+	if len(got) < len(want) {
+		t.Fatalf("Expected events starting %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected events starting %v, got %v", want, got)
+		}
+	}
+	if (*events)[0].Game.MoveCount != 6 {
+		t.Errorf("Imported game snapshot should include its moves")
+	}
+}
+
+// easiestPuzzle returns a puzzle of the given kind at the lowest difficulty
+func easiestPuzzle(t *testing.T, kind PuzzleKind) Puzzle {
+	t.Helper()
+	puzzle, err := RandomPuzzle(kind, MinPuzzleDifficulty)
+	if err != nil {
+		t.Fatalf("RandomPuzzle() failed: %v", err)
+	}
+	return puzzle
+}
+
+func TestEventBusUnsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	var mutex sync.Mutex
+	counts := make(map[string]int)
+	count := func(name string) EventHandler {
+		return func(Event) {
+			mutex.Lock()
+			counts[name]++
+			mutex.Unlock()
+		}
+	}
+
+	unsubscribeA := bus.Subscribe(count("a"))
+	bus.Subscribe(count("b"))
+	bus.Publish(Event{Type: EventGameCreated})
+	unsubscribeA()
+	unsubscribeA()
+	bus.Publish(Event{Type: EventGameDeleted})
+
+	if counts["a"] != 1 || counts["b"] != 2 {
+		t.Errorf("Unexpected deliveries: %v", counts)
+	}
+}
+
+func TestEventsConcurrentMoves(t *testing.T) {
+	engine := NewEngine()
+	var mutex sync.Mutex
+	var last uint64
+	ordered := true
+	engine.Events().Subscribe(func(e Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if e.Sequence != last+1 {
+			ordered = false
+		}
+		last = e.Sequence
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			engine.CreateGame(id)
+			for _, move := range []string{"A1", "B1", "A2", "B2", "A3"} {
+				game, _ := engine.GetGame(id)
+				pos, _ := ParsePosition(move)
+				engine.MakeMove(id, pos, game.CurrentPlayer)
+			}
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+
+	if !ordered || last != 8*(1+5+1) {
+		t.Errorf("Expected %d events in sequence order, got %d (ordered %v)", 8*7, last, ordered)
+	}
+}
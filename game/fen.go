@@ -0,0 +1,155 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Positions can be written in a compact, FEN-like notation: three rows from
+// row 1 to row 3 separated by slashes, using X, O and "." for empty cells,
+// followed by the side to move in lower case. For example:
+//
+//	X.O/.X./..O x
+//
+// is X in A1 and B2, O in C1 and C3, with X to move. The side to move may be
+// omitted, in which case it is inferred from the piece counts.
+
+// StartFEN is the notation for the empty starting position
+const StartFEN = ".../.../... x"
+
+// FormatFEN returns the compact notation for a board and side to move
+func FormatFEN(b Board, toMove Player) string {
+	var sb strings.Builder
+	for row := 0; row < 3; row++ {
+		if row > 0 {
+			sb.WriteByte('/')
+		}
+		for col := 0; col < 3; col++ {
+			switch b[row][col] {
+			case PlayerX:
+				sb.WriteByte('X')
+			case PlayerO:
+				sb.WriteByte('O')
+			default:
+				sb.WriteByte('.')
+			}
+		}
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(strings.ToLower(string(toMove)))
+	return sb.String()
+}
+
+// FEN returns the compact notation for the game's current position
+func (g *GameState) FEN() string {
+	return FormatFEN(g.Board, g.CurrentPlayer)
+}
+
+// ParseFEN parses the compact notation into a board and side to move, and
+// checks that the position could arise in a legal game
+func ParseFEN(fen string) (Board, Player, error) {
+	var board Board
+
+	fields := strings.Fields(fen)
+	if len(fields) == 0 || len(fields) > 2 {
+		return board, Empty, fmt.Errorf("position must be three rows and an optional side to move (e.g., X.O/.X./..O x)")
+	}
+
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != 3 {
+		return board, Empty, fmt.Errorf("position must have 3 rows separated by '/', got %d", len(rows))
+	}
+	for row, cells := range rows {
+		if len(cells) != 3 {
+			return board, Empty, fmt.Errorf("row %d must have 3 cells, got %d", row+1, len(cells))
+		}
+		for col := 0; col < 3; col++ {
+			switch cells[col] {
+			case 'X', 'x':
+				board[row][col] = PlayerX
+			case 'O', 'o':
+				board[row][col] = PlayerO
+			case '.', '-':
+				board[row][col] = Empty
+			default:
+				return board, Empty, fmt.Errorf("invalid cell %q in row %d (use X, O or .)", cells[col], row+1)
+			}
+		}
+	}
+
+	expected, err := ValidateBoard(board)
+	if err != nil {
+		return board, Empty, err
+	}
+
+	toMove := expected
+	if len(fields) == 2 {
+		switch strings.ToUpper(fields[1]) {
+		case "X":
+			toMove = PlayerX
+		case "O":
+			toMove = PlayerO
+		default:
+			return board, Empty, fmt.Errorf("side to move must be 'x' or 'o', got %q", fields[1])
+		}
+		if toMove != expected {
+			return board, Empty, fmt.Errorf("it must be %s to move with %d X and %d O on the board",
+				expected, board.Count(PlayerX), board.Count(PlayerO))
+		}
+	}
+
+	return board, toMove, nil
+}
+
+// ValidateBoard checks that a board is reachable in a legal game and returns
+// the player whose turn it is
+func ValidateBoard(board Board) (Player, error) {
+	xCount, oCount := board.Count(PlayerX), board.Count(PlayerO)
+
+	var toMove Player
+	switch xCount - oCount {
+	case 0:
+		toMove = PlayerX
+	case 1:
+		toMove = PlayerO
+	default:
+		return Empty, fmt.Errorf("illegal piece counts: %d X and %d O (X moves first, so X must equal O or O+1)", xCount, oCount)
+	}
+
+	xLines, oLines := board.CompletedLines(PlayerX), board.CompletedLines(PlayerO)
+	if len(xLines) > 0 && len(oLines) > 0 {
+		return Empty, fmt.Errorf("illegal position: both X and O have three in a row")
+	}
+	if len(xLines) > 0 && toMove != PlayerO {
+		return Empty, fmt.Errorf("illegal position: X has won but O has played as many moves")
+	}
+	if len(oLines) > 0 && toMove != PlayerX {
+		return Empty, fmt.Errorf("illegal position: O has won but X has played again since")
+	}
+
+	// A single final move can complete at most lines that all pass through it
+	for _, lines := range [][]Line{xLines, oLines} {
+		if len(lines) > 1 && !sharedCell(lines) {
+			return Empty, fmt.Errorf("illegal position: a player has won twice")
+		}
+	}
+
+	return toMove, nil
+}
+
+// sharedCell reports whether every line passes through one common position
+func sharedCell(lines []Line) bool {
+	for _, candidate := range lines[0] {
+		shared := true
+		for _, line := range lines[1:] {
+			if line[0] != candidate && line[1] != candidate && line[2] != candidate {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFEN(t *testing.T) {
+	board, toMove, err := ParseFEN("X.O/.X./..O x")
+	if err != nil {
+		t.Fatalf("ParseFEN() failed: %v", err)
+	}
+	if toMove != PlayerX {
+		t.Errorf("Expected X to move, got %s", toMove)
+	}
+	if board[0][0] != PlayerX || board[0][2] != PlayerO || board[1][1] != PlayerX || board[2][2] != PlayerO {
+		t.Errorf("Unexpected board:\n%s", board.String())
+	}
+	if got := FormatFEN(board, toMove); got != "X.O/.X./..O x" {
+		t.Errorf("FormatFEN() round trip gave %q", got)
+	}
+
+	// Side to move is inferred when omitted
+	if _, toMove, _ := ParseFEN("X../.../..."); toMove != PlayerO {
+		t.Errorf("Expected inferred O to move, got %s", toMove)
+	}
+
+	testCases := []struct {
+		fen    string
+		reason string
+	}{
+		{"X.O/.X.", "too few rows"},
+		{"X.O/.X./..Q x", "invalid cell"},
+		{"XX./.../... o", "too many X"},
+		{"O../.../... x", "O moved first"},
+		{"X.O/.X./..O o", "wrong side to move"},
+		{"XXX/OOO/... o", "both players won"},
+		{"XXX/OO./OO. x", "X won but O moved after"},
+		{"XXX/OOO/XXX o", "X won twice with disjoint lines"},
+	}
+	for _, tc := range testCases {
+		if _, _, err := ParseFEN(tc.fen); err == nil {
+			t.Errorf("Expected error for %q (%s)", tc.fen, tc.reason)
+		}
+	}
+
+	// Two lines through the last move are a legal double win
+	if _, _, err := ParseFEN("XXX/OXO/XOO o"); err != nil {
+		t.Errorf("Double win through C1 should be legal: %v", err)
+	}
+}
+
+func TestCreateGameFromPosition(t *testing.T) {
+	engine := NewEngine()
+
+	game, err := engine.CreateGameWithOptions("setup", GameOptions{FEN: "XX./OO./... x"})
+	if err != nil {
+		t.Fatalf("CreateGameWithOptions() failed: %v", err)
+	}
+	if game.Variant != VariantSetup || game.CurrentPlayer != PlayerX || game.MoveCount != 0 {
+		t.Errorf("Unexpected setup game: %+v", game)
+	}
+
+	pos, _ := ParsePosition("C1")
+	if game, _ = engine.MakeMove("setup", pos, PlayerX); game.Status != StatusWon {
+		t.Error("X should win by completing row 1")
+	}
+
+	game, _ = engine.ResetGame("setup")
+	if game.FEN() != "XX./OO./... x" || game.Status != StatusOngoing {
+		t.Errorf("Reset should return to the starting position, got %s", game.FEN())
+	}
+
+	if _, err := engine.CreateGameWithOptions("bad", GameOptions{FEN: "XX./.../... x"}); err == nil {
+		t.Error("Should reject an illegal position")
+	}
+
+	game, _ = engine.CreateGameWithOptions("finished", GameOptions{FEN: "XXX/OO./... o"})
+	if game.Status != StatusWon || game.Winner != PlayerX {
+		t.Errorf("Expected a finished game won by X, got %s", game.Status)
+	}
+}
+
+func TestSetupGameRecord(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGameWithOptions("setup", GameOptions{FEN: "X../.../... o"})
+
+	pos, _ := ParsePosition("B2")
+	engine.MakeMove("setup", pos, PlayerO)
+
+	text, _ := engine.ExportGame("setup")
+	if !strings.Contains(text, `[FEN "X../.../... o"]`) || !strings.Contains(text, "1... B2 *") {
+		t.Fatalf("Unexpected record:\n%s", text)
+	}
+
+	record, err := ParseRecord(text)
+	if err != nil {
+		t.Fatalf("ParseRecord() failed: %v", err)
+	}
+	game, err := engine.ImportGame("copy", record)
+	if err != nil {
+		t.Fatalf("ImportGame() failed: %v", err)
+	}
+	if game.FEN() != "X../.O./... x" {
+		t.Errorf("Unexpected imported position %s", game.FEN())
+	}
+}
@@ -0,0 +1,147 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hint levels, from a gentle nudge to the full answer
+const (
+	HintNudge   = 1 // A thematic pointer, e.g. "watch the diagonal"
+	HintThreats = 2 // The squares where lines can be completed or forked
+	HintMove    = 3 // The best move
+)
+
+// DefaultHintBudget is the number of hint points each game starts with. A
+// hint costs as many points as its level.
+const DefaultHintBudget = 6
+
+// Hint is advice for the player to move
+type Hint struct {
+	Level     int
+	Text      string
+	Squares   []Position // Squares named by threat hints
+	Move      *Position  // Suggested move, for move hints
+	Remaining int        // Hint points left after this hint
+}
+
+// GetHint spends hint points from the game's budget and returns a hint of the
+// given level for the player to move
+func (e *Engine) GetHint(gameID string, level int) (Hint, error) {
+	return e.GetHintAs(gameID, level, "")
+}
+
+// GetHintAs gets a hint for an authenticated principal. The side to move
+// must be free or bound to them; unlike a move, asking for a hint does not
+// bind it. An empty principal behaves like GetHint.
+func (e *Engine) GetHintAs(gameID string, level int, principal string) (Hint, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	game, exists := e.games[gameID]
+	if !exists {
+		return Hint{}, fmt.Errorf("game with ID %s not found", gameID)
+	}
+	if level < HintNudge || level > HintMove {
+		return Hint{}, fmt.Errorf("hint level must be between %d and %d", HintNudge, HintMove)
+	}
+	if game.IsGameOver() {
+		return Hint{}, fmt.Errorf("game is already over")
+	}
+	if err := checkSeat(game, game.CurrentPlayer, principal); err != nil {
+		return Hint{}, err
+	}
+
+	remaining := game.HintBudget - game.HintsUsed
+	if level > remaining {
+		return Hint{}, fmt.Errorf("hint budget exhausted: level %d costs %d, %d remaining", level, level, remaining)
+	}
+
+	var hint Hint
+	switch level {
+	case HintNudge:
+		hint = nudgeHint(game.Board, game.CurrentPlayer)
+	case HintThreats:
+		hint = threatHint(game.Board, game.CurrentPlayer)
+	case HintMove:
+		hint = moveHint(game.Board, game.CurrentPlayer)
+	}
+
+	game.HintsUsed += level
+	hint.Level = level
+	hint.Remaining = game.HintBudget - game.HintsUsed
+	return hint, nil
+}
+
+// nudgeHint points at the most urgent theme without naming squares
+func nudgeHint(board Board, player Player) Hint {
+	opponent := player.Opponent()
+
+	if own := board.Threats(player); len(own) > 0 {
+		return Hint{Text: fmt.Sprintf("Look along the %s. You may be closer to finishing than you think.", own[0].Line.Name())}
+	}
+	if theirs := board.Threats(opponent); len(theirs) > 0 {
+		return Hint{Text: fmt.Sprintf("Watch the %s. Your opponent is one move away from three in a row.", theirs[0].Line.Name())}
+	}
+	if len(board.ForkSquares(player)) > 0 {
+		return Hint{Text: "Look for a move that creates two threats at once. Your opponent can only block one."}
+	}
+	if len(board.ForkSquares(opponent)) > 0 {
+		return Hint{Text: "Your opponent is eyeing a square that would give them two threats at once. Think about which of their lines meet."}
+	}
+	if board.IsEmpty(Position{Row: 1, Col: 1}) {
+		return Hint{Text: "The centre square sits on four lines, more than any other."}
+	}
+	return Hint{Text: "Corners sit on three lines each. Think about which lines are still open to both players."}
+}
+
+// threatHint names the squares that complete lines or create forks
+func threatHint(board Board, player Player) Hint {
+	opponent := player.Opponent()
+	var parts []string
+	var squares []Position
+
+	describe := func(label string, positions []Position) {
+		if len(positions) == 0 {
+			return
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", label, joinPositions(positions)))
+		squares = append(squares, positions...)
+	}
+
+	describe("You can win at", board.WinningSquares(player))
+	describe("Your opponent wins next at", board.WinningSquares(opponent))
+	if len(parts) == 0 {
+		describe("You can create a fork at", board.ForkSquares(player))
+		describe("Your opponent could fork at", board.ForkSquares(opponent))
+	}
+
+	if len(parts) == 0 {
+		return Hint{Text: "No square completes or forks a line yet. Build toward open lines."}
+	}
+	return Hint{Text: strings.Join(parts, ". ") + ".", Squares: squares}
+}
+
+// moveHint gives the solver's best move
+func moveHint(board Board, player Player) Hint {
+	best := BestMoves(board, player)
+	if len(best) == 0 {
+		return Hint{Text: "There are no moves left."}
+	}
+
+	move := best[0]
+	next := board
+	next.Set(move, player)
+	eval := Evaluate(next, player.Opponent())
+
+	var outlook string
+	switch eval.Outcome {
+	case OutcomeLoss:
+		outlook = "and wins with correct play"
+	case OutcomeWin:
+		outlook = "though the position is lost against perfect defence"
+	default:
+		outlook = "and holds the draw"
+	}
+	return Hint{Text: fmt.Sprintf("Play %s %s.", move, outlook), Move: &move, Squares: []Position{move}}
+}
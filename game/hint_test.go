@@ -0,0 +1,69 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetHint(t *testing.T) {
+	engine := NewEngine()
+
+	// O to move must block X's row 1 threat at C1
+	engine.CreateGameWithOptions("hints", GameOptions{FEN: "XX./O../... o"})
+
+	hint, err := engine.GetHint("hints", HintNudge)
+	if err != nil {
+		t.Fatalf("GetHint() failed: %v", err)
+	}
+	if !strings.Contains(hint.Text, "row 1") {
+		t.Errorf("Nudge should point at row 1, got %q", hint.Text)
+	}
+	if hint.Remaining != DefaultHintBudget-1 {
+		t.Errorf("Expected %d points remaining, got %d", DefaultHintBudget-1, hint.Remaining)
+	}
+
+	hint, _ = engine.GetHint("hints", HintThreats)
+	if len(hint.Squares) != 1 || hint.Squares[0].String() != "C1" {
+		t.Errorf("Threat hint should name C1, got %v (%q)", hint.Squares, hint.Text)
+	}
+
+	hint, _ = engine.GetHint("hints", HintMove)
+	if hint.Move == nil || hint.Move.String() != "C1" {
+		t.Errorf("Move hint should suggest C1, got %q", hint.Text)
+	}
+	if hint.Remaining != 0 {
+		t.Errorf("Expected the budget to be spent, got %d remaining", hint.Remaining)
+	}
+
+	if _, err := engine.GetHint("hints", HintNudge); err == nil {
+		t.Error("Should refuse hints once the budget is spent")
+	}
+	if _, err := engine.GetHint("hints", 4); err == nil {
+		t.Error("Should reject unknown hint levels")
+	}
+
+	game, _ := engine.ResetGame("hints")
+	if game.HintsUsed != 0 {
+		t.Error("Reset should restore the hint budget")
+	}
+}
+
+func TestThreats(t *testing.T) {
+	board, _, _ := ParseFEN("X.X/.O./O.X o")
+
+	threats := board.Threats(PlayerX)
+	if len(threats) != 2 {
+		t.Fatalf("Expected 2 threats for X, got %+v", threats)
+	}
+	if threats[0].Line.Name() != "row 1" || threats[0].Square.String() != "B1" {
+		t.Errorf("Unexpected first threat %s at %s", threats[0].Line.Name(), threats[0].Square)
+	}
+	if threats[1].Line.Name() != "column C" || threats[1].Square.String() != "C2" {
+		t.Errorf("Unexpected second threat %s at %s", threats[1].Line.Name(), threats[1].Square)
+	}
+
+	board, _, _ = ParseFEN("X../.O./... x")
+	if forks := board.ForkSquares(PlayerX); len(forks) != 0 {
+		t.Errorf("X should have no fork squares yet, got %v", forks)
+	}
+}
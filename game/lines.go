@@ -0,0 +1,137 @@
+package game
+
+import "fmt"
+
+// Line is a row, column or diagonal of three positions
+type Line [3]Position
+
+// winLines lists the eight lines that win the game when filled by one player
+var winLines = [8]Line{
+	{{0, 0}, {0, 1}, {0, 2}}, // Row 1
+	{{1, 0}, {1, 1}, {1, 2}}, // Row 2
+	{{2, 0}, {2, 1}, {2, 2}}, // Row 3
+	{{0, 0}, {1, 0}, {2, 0}}, // Column A
+	{{0, 1}, {1, 1}, {2, 1}}, // Column B
+	{{0, 2}, {1, 2}, {2, 2}}, // Column C
+	{{0, 0}, {1, 1}, {2, 2}}, // Diagonal A1-C3
+	{{0, 2}, {1, 1}, {2, 0}}, // Diagonal C1-A3
+}
+
+// CompletedLines returns the lines fully occupied by the given player
+func (b *Board) CompletedLines(player Player) []Line {
+	var completed []Line
+	for _, line := range winLines {
+		if b.Get(line[0]) == player && b.Get(line[1]) == player && b.Get(line[2]) == player {
+			completed = append(completed, line)
+		}
+	}
+	return completed
+}
+
+// Count returns the number of cells occupied by the given player
+func (b *Board) Count(player Player) int {
+	count := 0
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			if b[row][col] == player {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// EmptyPositions returns the unoccupied positions in row-major order
+func (b *Board) EmptyPositions() []Position {
+	var positions []Position
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			if b[row][col] == Empty {
+				positions = append(positions, Position{Row: row, Col: col})
+			}
+		}
+	}
+	return positions
+}
+
+// WinningSquares returns the empty positions where the player would complete
+// a line, in row-major order
+func (b *Board) WinningSquares(player Player) []Position {
+	var squares []Position
+	for _, pos := range b.EmptyPositions() {
+		if b.completesLine(pos, player) {
+			squares = append(squares, pos)
+		}
+	}
+	return squares
+}
+
+// ForkSquares returns the empty positions where a move by the player would
+// create two or more winning squares at once
+func (b *Board) ForkSquares(player Player) []Position {
+	var squares []Position
+	for _, pos := range b.EmptyPositions() {
+		next := *b
+		next.Set(pos, player)
+		if len(next.WinningSquares(player)) >= 2 {
+			squares = append(squares, pos)
+		}
+	}
+	return squares
+}
+
+// completesLine reports whether playing pos would give the player a line
+func (b *Board) completesLine(pos Position, player Player) bool {
+	for _, line := range winLines {
+		onLine, others := false, 0
+		for _, p := range line {
+			if p == pos {
+				onLine = true
+			} else if b.Get(p) == player {
+				others++
+			}
+		}
+		if onLine && others == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// Name describes the line, e.g. "row 1", "column B" or "diagonal A1-C3"
+func (l Line) Name() string {
+	switch {
+	case l[0].Row == l[2].Row:
+		return fmt.Sprintf("row %d", l[0].Row+1)
+	case l[0].Col == l[2].Col:
+		return fmt.Sprintf("column %c", 'A'+l[0].Col)
+	}
+	return fmt.Sprintf("diagonal %s-%s", l[0], l[2])
+}
+
+// Threat is a line where a player holds two cells and the third is empty
+type Threat struct {
+	Player Player   `json:"player"`
+	Square Position `json:"square"` // The empty cell that would complete the line
+	Line   Line     `json:"line"`
+}
+
+// Threats returns the player's open two-in-a-rows, in line order
+func (b *Board) Threats(player Player) []Threat {
+	var threats []Threat
+	for _, line := range winLines {
+		owned, empty := 0, -1
+		for i, pos := range line {
+			switch b.Get(pos) {
+			case player:
+				owned++
+			case Empty:
+				empty = i
+			}
+		}
+		if owned == 2 && empty >= 0 {
+			threats = append(threats, Threat{Player: player, Square: line[empty], Line: line})
+		}
+	}
+	return threats
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MoveObserver is told how long each MakeMove call took, including the wait
+// for the engine lock, and the error it returned, if any
+type MoveObserver func(elapsed time.Duration, err error)
+
+// engineObservers holds the engine's instrumentation hooks
+type engineObservers struct {
+	move atomic.Pointer[MoveObserver]
+}
+
+// ObserveMoves sets the function told about every MakeMove call, replacing
+// any previous one; nil removes it. It runs after the engine lock is
+// released, so it may call back into the Engine.
+func (e *Engine) ObserveMoves(observer MoveObserver) {
+	if observer == nil {
+		e.observers.move.Store(nil)
+		return
+	}
+	e.observers.move.Store(&observer)
+}
+
+// observeMove reports a MakeMove call that began at start
+func (e *Engine) observeMove(start time.Time, err error) {
+	if observer := e.observers.move.Load(); observer != nil {
+		(*observer)(time.Since(start), err)
+	}
+}
+
+// CountGames returns how many games the engine holds in each status
+func (e *Engine) CountGames() map[GameStatus]int {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	counts := map[GameStatus]int{
+		StatusOngoing: 0,
+		StatusWon:     0,
+		StatusDraw:    0,
+	}
+	for _, game := range e.games {
+		counts[game.Status]++
+	}
+	return counts
+}
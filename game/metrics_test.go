@@ -0,0 +1,54 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObserveMoves(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGame("observed")
+
+	var calls int
+	var failures int
+	engine.ObserveMoves(func(elapsed time.Duration, err error) {
+		calls++
+		if err != nil {
+			failures++
+		}
+		if elapsed < 0 {
+			t.Errorf("Negative move duration %v", elapsed)
+		}
+		// Observers run outside the lock, so calling back in must not deadlock
+		engine.CountGames()
+	})
+
+	engine.MakeMove("observed", Position{Row: 1, Col: 1}, PlayerX)
+	engine.MakeMove("observed", Position{Row: 1, Col: 1}, PlayerO)
+	engine.MakeMove("missing", Position{Row: 0, Col: 0}, PlayerX)
+	if calls != 3 || failures != 2 {
+		t.Errorf("Expected 3 observed moves with 2 failures, got %d and %d", calls, failures)
+	}
+
+	engine.ObserveMoves(nil)
+	engine.MakeMove("observed", Position{Row: 0, Col: 0}, PlayerO)
+	if calls != 3 {
+		t.Errorf("Expected no calls after removing the observer, got %d", calls)
+	}
+}
+
+func TestCountGames(t *testing.T) {
+	engine := NewEngine()
+	if counts := engine.CountGames(); counts[StatusOngoing] != 0 || len(counts) != 3 {
+		t.Errorf("Expected zero counts for every status, got %v", counts)
+	}
+
+	engine.CreateGame("ongoing")
+	engine.CreateGameWithOptions("won", GameOptions{FEN: "XXX/OO./... o"})
+	engine.CreateGameWithOptions("drawn", GameOptions{FEN: "XOX/XOO/OXX o"})
+
+	counts := engine.CountGames()
+	if counts[StatusOngoing] != 1 || counts[StatusWon] != 1 || counts[StatusDraw] != 1 {
+		t.Errorf("Expected one game in each status, got %v", counts)
+	}
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpeningStats counts the results of the games that reached a position
+type OpeningStats struct {
+	Games int `json:"games"`
+	XWins int `json:"x_wins"`
+	Draws int `json:"draws"`
+	OWins int `json:"o_wins"`
+	Plies int `json:"plies"` // Total length of those games, for AverageLength
+}
+
+// Percentages returns the share of X wins, draws and O wins, out of 100
+func (s OpeningStats) Percentages() (xWins, draws, oWins float64) {
+	if s.Games == 0 {
+		return 0, 0, 0
+	}
+	n := float64(s.Games)
+	return 100 * float64(s.XWins) / n, 100 * float64(s.Draws) / n, 100 * float64(s.OWins) / n
+}
+
+// AverageLength returns the mean number of moves in the games counted
+func (s OpeningStats) AverageLength() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Plies) / float64(s.Games)
+}
+
+func (s *OpeningStats) add(winner Player, plies int) {
+	s.Games++
+	s.Plies += plies
+	switch winner {
+	case PlayerX:
+		s.XWins++
+	case PlayerO:
+		s.OWins++
+	default:
+		s.Draws++
+	}
+}
+
+// OpeningNode is a move prefix in an opening tree. Children are keyed by the
+// next move.
+type OpeningNode struct {
+	OpeningStats
+	Children map[Position]*OpeningNode
+}
+
+// BuildOpeningTree aggregates finished games that started from the empty
+// board into a tree of move prefixes. Other games are ignored.
+func BuildOpeningTree(games []*GameState) *OpeningNode {
+	root := &OpeningNode{}
+	for _, g := range games {
+		if !g.IsGameOver() || g.StartFEN != "" {
+			continue
+		}
+		node := root
+		node.add(g.Winner, len(g.Moves))
+		for _, move := range g.Moves {
+			if node.Children == nil {
+				node.Children = make(map[Position]*OpeningNode)
+			}
+			child, ok := node.Children[move.Position]
+			if !ok {
+				child = &OpeningNode{}
+				node.Children[move.Position] = child
+			}
+			child.add(g.Winner, len(g.Moves))
+			node = child
+		}
+	}
+	return root
+}
+
+// Lookup returns the node for a move prefix, or nil if no game played it
+func (n *OpeningNode) Lookup(moves []Position) *OpeningNode {
+	node := n
+	for _, move := range moves {
+		if node = node.Children[move]; node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// Continuation is a move played from an opening position and how the games
+// that played it turned out
+type Continuation struct {
+	Move Position `json:"move"`
+	OpeningStats
+	// Evaluation is the solved value of the move for the player making it
+	Evaluation Evaluation `json:"evaluation"`
+}
+
+// OpeningReport describes a move prefix across the stored games
+type OpeningReport struct {
+	Moves         []Position     `json:"moves"`
+	ToMove        Player         `json:"to_move"`
+	FEN           string         `json:"fen"`
+	Stats         OpeningStats   `json:"stats"`
+	Evaluation    Evaluation     `json:"evaluation"` // Solved value for the side to move
+	Continuations []Continuation `json:"continuations"`
+}
+
+// ExploreOpening reports how finished games that began with the given moves
+// continued and scored. The moves must form a legal, undecided sequence
+// from the empty board; the report is empty if no stored game played them.
+func (e *Engine) ExploreOpening(moves []Position) (*OpeningReport, error) {
+	board, toMove := NewBoard(), PlayerX
+	for i, move := range moves {
+		if move.Row < 0 || move.Row > 2 || move.Col < 0 || move.Col > 2 {
+			return nil, fmt.Errorf("move %d is off the board", i+1)
+		}
+		if !board.IsEmpty(move) {
+			return nil, fmt.Errorf("move %d: %s is already occupied", i+1, move)
+		}
+		if bitboardOf(&board).terminal(toMove) {
+			return nil, fmt.Errorf("the game is already over before move %d", i+1)
+		}
+		board.Set(move, toMove)
+		toMove = toMove.Opponent()
+	}
+
+	e.mutex.RLock()
+	games := make([]*GameState, 0, len(e.games))
+	for _, g := range e.games {
+		games = append(games, g)
+	}
+	tree := BuildOpeningTree(games)
+	e.mutex.RUnlock()
+
+	report := &OpeningReport{
+		Moves:         append([]Position{}, moves...),
+		ToMove:        toMove,
+		FEN:           FormatFEN(board, toMove),
+		Evaluation:    Evaluate(board, toMove),
+		Continuations: []Continuation{},
+	}
+	node := tree.Lookup(moves)
+	if node == nil {
+		return report, nil
+	}
+	report.Stats = node.OpeningStats
+
+	values := make(map[Position]Evaluation)
+	if !bitboardOf(&board).terminal(toMove) {
+		for _, mv := range EvaluateMoves(board, toMove) {
+			values[mv.Position] = mv.Evaluation
+		}
+	}
+	for move, child := range node.Children {
+		report.Continuations = append(report.Continuations, Continuation{
+			Move:         move,
+			OpeningStats: child.OpeningStats,
+			Evaluation:   values[move],
+		})
+	}
+	sort.Slice(report.Continuations, func(i, j int) bool {
+		a, b := report.Continuations[i], report.Continuations[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Move.Row*3+a.Move.Col < b.Move.Row*3+b.Move.Col
+	})
+	return report, nil
+}
+
+// String renders the report as a table of continuations
+func (r *OpeningReport) String() string {
+	var sb strings.Builder
+	opening := "start position"
+	if len(r.Moves) > 0 {
+		opening = joinMoves(r.Moves)
+	}
+	fmt.Fprintf(&sb, "Opening: %s (%s to move, %s)\n", opening, r.ToMove, r.Evaluation)
+
+	if r.Stats.Games == 0 {
+		sb.WriteString("No finished games reached this position")
+		return sb.String()
+	}
+
+	x, draw, o := r.Stats.Percentages()
+	fmt.Fprintf(&sb, "Games: %d | X wins %.1f%% | draws %.1f%% | O wins %.1f%% | avg %.1f moves\n",
+		r.Stats.Games, x, draw, o, r.Stats.AverageLength())
+
+	if len(r.Continuations) == 0 {
+		sb.WriteString("Every game ended here")
+		return sb.String()
+	}
+	sb.WriteString("Continuations:")
+	for _, c := range r.Continuations {
+		x, draw, o := c.Percentages()
+		fmt.Fprintf(&sb, "\n- %s: %d games (%.1f%%) | X %.1f%% | draw %.1f%% | O %.1f%% | avg %.1f moves | theory: %s",
+			c.Move, c.Games, 100*float64(c.Games)/float64(r.Stats.Games), x, draw, o, c.AverageLength(), c.Evaluation)
+	}
+	return sb.String()
+}
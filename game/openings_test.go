@@ -0,0 +1,55 @@
+package game
+
+import "testing"
+
+func TestExploreOpening(t *testing.T) {
+	engine := NewEngine()
+
+	engine.CreateGame("x-wins")
+	playMoves(t, engine, "x-wins", "B2", "B1", "A1", "C3", "A3", "C1", "A2")
+	engine.CreateGame("o-wins")
+	playMoves(t, engine, "o-wins", "B2", "A1", "C2", "A2", "C1", "A3")
+	engine.CreateGame("draw")
+	playMoves(t, engine, "draw", "B2", "A1", "C3", "A3", "A2", "C2", "B1", "B3", "C1")
+	engine.CreateGame("ongoing")
+	playMoves(t, engine, "ongoing", "B2", "A1")
+	engine.CreateGameWithOptions("setup", GameOptions{FEN: "XX./OO./... x"})
+	playMoves(t, engine, "setup", "C1")
+
+	report, err := engine.ExploreOpening(nil)
+	if err != nil {
+		t.Fatalf("ExploreOpening() failed: %v", err)
+	}
+	if report.Stats.Games != 3 || report.Stats.XWins != 1 || report.Stats.OWins != 1 || report.Stats.Draws != 1 {
+		t.Errorf("Only finished games from the empty board should count, got %+v", report.Stats)
+	}
+	if len(report.Continuations) != 1 || report.Continuations[0].Move.String() != "B2" {
+		t.Errorf("Expected the single continuation B2, got %+v", report.Continuations)
+	}
+
+	a1, _ := ParsePosition("A1")
+	b2, _ := ParsePosition("B2")
+	report, _ = engine.ExploreOpening([]Position{b2})
+	if report.ToMove != PlayerO || len(report.Continuations) != 2 {
+		t.Fatalf("Unexpected report after B2: %+v", report)
+	}
+	first := report.Continuations[0]
+	if first.Move != a1 || first.Games != 2 || first.Evaluation.Outcome != OutcomeDraw {
+		t.Errorf("Expected A1 to be the most played, drawing continuation, got %+v", first)
+	}
+	if x, draw, o := first.Percentages(); x != 0 || draw != 50 || o != 50 {
+		t.Errorf("Unexpected percentages %.1f/%.1f/%.1f", x, draw, o)
+	}
+	if first.AverageLength() != 7.5 {
+		t.Errorf("Expected an average length of 7.5, got %.2f", first.AverageLength())
+	}
+
+	report, _ = engine.ExploreOpening([]Position{a1})
+	if report.Stats.Games != 0 || len(report.Continuations) != 0 {
+		t.Errorf("No game opened A1, got %+v", report)
+	}
+
+	if _, err := engine.ExploreOpening([]Position{b2, b2}); err == nil {
+		t.Error("Expected an error for a repeated square")
+	}
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSeatHeld is wrapped by the errors for moves and claims on a mark bound
+// to another principal
+var ErrSeatHeld = errors.New("seat taken")
+
+// SetOwner records the authenticated principal that owns a game
+func (e *Engine) SetOwner(gameID, owner string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	game, exists := e.games[gameID]
+	if !exists {
+		return fmt.Errorf("game with ID %s not found", gameID)
+	}
+	game.Owner = owner
+	return nil
+}
+
+// ClaimSeat binds a mark in a game to a principal, so that only they can
+// play it from then on. Claiming a seat already held by the same principal
+// succeeds; claiming one held by someone else fails.
+func (e *Engine) ClaimSeat(gameID string, player Player, principal string) error {
+	if player != PlayerX && player != PlayerO {
+		return fmt.Errorf("invalid player: %s", player)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	game, exists := e.games[gameID]
+	if !exists {
+		return fmt.Errorf("game with ID %s not found", gameID)
+	}
+	if err := checkSeat(game, player, principal); err != nil {
+		return err
+	}
+	bindSeat(game, player, principal)
+	return nil
+}
+
+// CheckOwner fails unless the principal may replace, reset or delete the
+// game. Unauthenticated callers, with an empty principal, always may;
+// authenticated ones only for games they created.
+func (g *GameState) CheckOwner(principal string) error {
+	switch {
+	case principal == "" || g.Owner == principal:
+		return nil
+	case g.Owner == "":
+		return fmt.Errorf("game %s was created without authentication, so only unauthenticated clients may change it", g.GameID)
+	}
+	return fmt.Errorf("game %s belongs to %s", g.GameID, g.Owner)
+}
+
+// checkSeat fails if the mark is bound to a principal other than the given
+// one. An empty principal, an unauthenticated caller, is not checked.
+func checkSeat(game *GameState, player Player, principal string) error {
+	if principal == "" {
+		return nil
+	}
+	if holder := game.Seats[player]; holder != "" && holder != principal {
+		return fmt.Errorf("%w: seat %s in game %s is held by %s", ErrSeatHeld, player, game.GameID, holder)
+	}
+	return nil
+}
+
+// bindSeat binds the mark to the principal, if there is one
+func bindSeat(game *GameState, player Player, principal string) {
+	if principal == "" {
+		return
+	}
+	if game.Seats == nil {
+		game.Seats = make(map[Player]string)
+	}
+	game.Seats[player] = principal
+}
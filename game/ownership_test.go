@@ -0,0 +1,124 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClaimSeat(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGameWithOptions("seated", GameOptions{Owner: "alice"})
+
+	if err := engine.ClaimSeat("seated", PlayerX, "alice"); err != nil {
+		t.Fatalf("Claiming a free seat failed: %v", err)
+	}
+	if err := engine.ClaimSeat("seated", PlayerX, "alice"); err != nil {
+		t.Errorf("Reclaiming one's own seat failed: %v", err)
+	}
+	err := engine.ClaimSeat("seated", PlayerX, "bob")
+	if err == nil || !strings.Contains(err.Error(), "held by alice") {
+		t.Errorf("Expected the seat to be held by alice, got %v", err)
+	}
+	if err := engine.ClaimSeat("seated", PlayerO, "bob"); err != nil {
+		t.Errorf("Claiming the other seat failed: %v", err)
+	}
+
+	game, _ := engine.Snapshot("seated")
+	if game.Owner != "alice" || game.Seats[PlayerX] != "alice" || game.Seats[PlayerO] != "bob" {
+		t.Errorf("Unexpected owner %q and seats %v", game.Owner, game.Seats)
+	}
+
+	// Seats survive a reset, so the same participants play again
+	engine.ResetGame("seated")
+	if game, _ := engine.Snapshot("seated"); game.Seats[PlayerO] != "bob" {
+		t.Errorf("Expected seats to survive a reset, got %v", game.Seats)
+	}
+
+	if err := engine.ClaimSeat("missing", PlayerX, "alice"); err == nil {
+		t.Error("Expected an error for a missing game")
+	}
+	if err := engine.ClaimSeat("seated", Empty, "alice"); err == nil {
+		t.Error("Expected an error for an invalid player")
+	}
+}
+
+func TestSetOwner(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGame("owned")
+	if err := engine.SetOwner("owned", "carol"); err != nil {
+		t.Fatalf("SetOwner failed: %v", err)
+	}
+	if game, _ := engine.Snapshot("owned"); game.Owner != "carol" {
+		t.Errorf("Expected owner carol, got %q", game.Owner)
+	}
+	if err := engine.SetOwner("missing", "carol"); err == nil {
+		t.Error("Expected an error for a missing game")
+	}
+}
+
+func TestMakeMoveAsBindsSeatOnSuccess(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGame("seated")
+
+	if _, err := engine.MakeMoveAs("seated", Position{Row: 1, Col: 1}, PlayerO, "bob"); err == nil {
+		t.Fatal("Expected O to be refused out of turn")
+	}
+	if game, _ := engine.Snapshot("seated"); len(game.Seats) != 0 {
+		t.Errorf("A rejected move bound a seat: %v", game.Seats)
+	}
+
+	if _, err := engine.MakeMoveAs("seated", Position{Row: 1, Col: 1}, PlayerX, "alice"); err != nil {
+		t.Fatalf("MakeMoveAs failed: %v", err)
+	}
+	_, err := engine.MakeMoveAs("seated", Position{Row: 0, Col: 0}, PlayerO, "bob")
+	if err != nil {
+		t.Fatalf("MakeMoveAs for O failed: %v", err)
+	}
+	_, err = engine.MakeMoveAs("seated", Position{Row: 0, Col: 2}, PlayerX, "bob")
+	if !errors.Is(err, ErrSeatHeld) {
+		t.Errorf("Expected ErrSeatHeld, got %v", err)
+	}
+	if game, _ := engine.Snapshot("seated"); game.Seats[PlayerX] != "alice" || game.Seats[PlayerO] != "bob" || len(game.Moves) != 2 {
+		t.Errorf("Unexpected seats %v after %d moves", game.Seats, len(game.Moves))
+	}
+}
+
+func TestGetHintAsChecksSeat(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGame("hinted")
+	engine.MakeMoveAs("hinted", Position{Row: 1, Col: 1}, PlayerX, "alice")
+
+	// O is free, so anyone may ask, and asking does not take the seat
+	if _, err := engine.GetHintAs("hinted", HintNudge, "carol"); err != nil {
+		t.Fatalf("GetHintAs for a free seat failed: %v", err)
+	}
+	if game, _ := engine.Snapshot("hinted"); game.Seats[PlayerO] != "" {
+		t.Errorf("A hint bound seat O to %q", game.Seats[PlayerO])
+	}
+
+	engine.MakeMoveAs("hinted", Position{Row: 0, Col: 0}, PlayerO, "bob")
+	if _, err := engine.GetHintAs("hinted", HintNudge, "bob"); !errors.Is(err, ErrSeatHeld) {
+		t.Errorf("Expected ErrSeatHeld for bob asking on X's turn, got %v", err)
+	}
+	if game, _ := engine.Snapshot("hinted"); game.HintsUsed != HintNudge {
+		t.Errorf("A refused hint spent points: %d used", game.HintsUsed)
+	}
+	if _, err := engine.GetHintAs("hinted", HintNudge, "alice"); err != nil {
+		t.Errorf("GetHintAs for the seat holder failed: %v", err)
+	}
+}
+
+func TestCheckOwner(t *testing.T) {
+	owned := &GameState{GameID: "owned", Owner: "alice"}
+	unowned := &GameState{GameID: "unowned"}
+	if owned.CheckOwner("alice") != nil || owned.CheckOwner("") != nil || unowned.CheckOwner("") != nil {
+		t.Error("Expected the owner and unauthenticated callers to pass")
+	}
+	if err := owned.CheckOwner("bob"); err == nil || !strings.Contains(err.Error(), "belongs to alice") {
+		t.Errorf("Expected bob to be refused, got %v", err)
+	}
+	if unowned.CheckOwner("bob") == nil {
+		t.Error("Expected an authenticated caller to be refused a game created without authentication")
+	}
+}
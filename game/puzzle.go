@@ -0,0 +1,253 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+)
+
+// PuzzleKind identifies the tactical theme of a puzzle
+type PuzzleKind string
+
+const (
+	PuzzleWinInN    PuzzleKind = "win-in-n"   // Force a win in N moves
+	PuzzleOnlyDraw  PuzzleKind = "only-draw"  // Find the single move that avoids losing
+	PuzzleBlockFork PuzzleKind = "block-fork" // Stop the opponent from setting up a fork
+)
+
+// PuzzleKinds lists every puzzle kind
+var PuzzleKinds = []PuzzleKind{PuzzleWinInN, PuzzleOnlyDraw, PuzzleBlockFork}
+
+const (
+	MinPuzzleDifficulty = 1
+	MaxPuzzleDifficulty = 5
+)
+
+// VariantPuzzle marks games created to solve a puzzle
+const VariantPuzzle Variant = "puzzle"
+
+// Puzzle is a position with a set of correct first moves
+type Puzzle struct {
+	ID         string
+	Kind       PuzzleKind
+	FEN        string
+	ToMove     Player
+	Solutions  []Position
+	WinIn      int // For win-in-n puzzles, the number of moves by the solver
+	Difficulty int // 1 (easiest) to 5
+	Prompt     string
+}
+
+// IsSolution reports whether pos is one of the puzzle's correct moves
+func (p *Puzzle) IsSolution(pos Position) bool {
+	for _, solution := range p.Solutions {
+		if solution == pos {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	puzzlesOnce sync.Once
+	puzzles     []Puzzle
+)
+
+// Puzzles returns every generated puzzle, ordered by kind, difficulty and ID.
+// They are derived once from all positions reachable from the empty board.
+func Puzzles() []Puzzle {
+	puzzlesOnce.Do(func() {
+		puzzles = generatePuzzles()
+	})
+	return puzzles
+}
+
+// FindPuzzle returns a puzzle by ID
+func FindPuzzle(id string) (Puzzle, error) {
+	for _, puzzle := range Puzzles() {
+		if puzzle.ID == id {
+			return puzzle, nil
+		}
+	}
+	return Puzzle{}, fmt.Errorf("puzzle %s not found", id)
+}
+
+// RandomPuzzle picks a puzzle at random. An empty kind or a zero difficulty
+// matches any puzzle.
+func RandomPuzzle(kind PuzzleKind, difficulty int) (Puzzle, error) {
+	var candidates []Puzzle
+	for _, puzzle := range Puzzles() {
+		if (kind == "" || puzzle.Kind == kind) && (difficulty == 0 || puzzle.Difficulty == difficulty) {
+			candidates = append(candidates, puzzle)
+		}
+	}
+	if len(candidates) == 0 {
+		return Puzzle{}, fmt.Errorf("no puzzles match kind %q and difficulty %d", kind, difficulty)
+	}
+	return candidates[rand.IntN(len(candidates))], nil
+}
+
+// generatePuzzles classifies every reachable, undecided position
+func generatePuzzles() []Puzzle {
+	var result []Puzzle
+	seen := make(map[boardKey]bool)
+
+	var visit func(board Board, toMove Player)
+	visit = func(board Board, toMove Player) {
+		key := keyOf(board)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		if bitboardOf(&board).terminal(toMove) {
+			return
+		}
+		if puzzle, ok := classifyPosition(board, toMove); ok {
+			result = append(result, puzzle)
+		}
+		for _, pos := range board.EmptyPositions() {
+			next := board
+			next.Set(pos, toMove)
+			visit(next, toMove.Opponent())
+		}
+	}
+	visit(NewBoard(), PlayerX)
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Difficulty != b.Difficulty {
+			return a.Difficulty < b.Difficulty
+		}
+		return a.ID < b.ID
+	})
+	return result
+}
+
+// classifyPosition decides whether a position makes a good puzzle, and of
+// which kind. Positions where every move is equally good are skipped.
+func classifyPosition(board Board, toMove Player) (Puzzle, bool) {
+	moves := defaultSolver.EvaluateMoves(board, toMove)
+	position := defaultSolver.Evaluate(board, toMove)
+	opponent := toMove.Opponent()
+
+	var solutions []Position
+	for _, move := range moves {
+		if !position.Better(move.Evaluation) {
+			solutions = append(solutions, move.Position)
+		}
+	}
+	if len(solutions) == len(moves) {
+		return Puzzle{}, false
+	}
+
+	puzzle := Puzzle{
+		FEN:       FormatFEN(board, toMove),
+		ToMove:    toMove,
+		Solutions: solutions,
+	}
+
+	// Hidden gems among many plausible moves are harder to spot
+	hidden := 0
+	if len(solutions) == 1 && len(moves) >= 5 {
+		hidden = 1
+	}
+
+	switch {
+	case position.Outcome == OutcomeWin:
+		puzzle.Kind = PuzzleWinInN
+		puzzle.WinIn = (position.Plies + 1) / 2
+		puzzle.Difficulty = 2*puzzle.WinIn - 1 + hidden
+		puzzle.Prompt = fmt.Sprintf("%s to play and win in %d.", toMove, puzzle.WinIn)
+	case position.Outcome == OutcomeDraw && len(board.WinningSquares(opponent)) == 0 && len(board.ForkSquares(opponent)) > 0:
+		puzzle.Kind = PuzzleBlockFork
+		puzzle.Difficulty = 3 + hidden
+		puzzle.Prompt = fmt.Sprintf("%s threatens to set up a fork. %s to play and hold the draw.", opponent, toMove)
+	case position.Outcome == OutcomeDraw && len(solutions) == 1:
+		puzzle.Kind = PuzzleOnlyDraw
+		puzzle.Difficulty = 2 + hidden
+		if len(board.WinningSquares(opponent)) > 0 {
+			// Blocking an open line is the most obvious defence there is
+			puzzle.Difficulty = 1
+		}
+		puzzle.Prompt = fmt.Sprintf("%s to play. Only one move avoids losing.", toMove)
+	default:
+		return Puzzle{}, false
+	}
+
+	puzzle.ID = fmt.Sprintf("%s-%05d", puzzle.Kind, keyOf(board))
+	if puzzle.Difficulty > MaxPuzzleDifficulty {
+		puzzle.Difficulty = MaxPuzzleDifficulty
+	}
+	return puzzle, true
+}
+
+// CreatePuzzleGame starts a game at the puzzle's position
+func (e *Engine) CreatePuzzleGame(gameID string, puzzle Puzzle) (*GameState, error) {
+	return e.CreateGameWithOptions(gameID, PuzzleOptions(puzzle))
+}
+
+// PuzzleOptions returns the options that start a game at the puzzle's
+// position; callers may add an owner or players before creating the game
+func PuzzleOptions(puzzle Puzzle) GameOptions {
+	return GameOptions{
+		FEN:     puzzle.FEN,
+		Variant: VariantPuzzle,
+		Puzzle:  &puzzle,
+	}
+}
+
+// CheckPuzzleAnswer checks a proposed first move for a puzzle game. A correct
+// answer is played on the board and marks the puzzle solved; a wrong answer
+// only counts as a failed attempt. Once a move has been played the game is no
+// longer at the puzzle position, so answers are rejected until it is reset.
+func (e *Engine) CheckPuzzleAnswer(gameID string, pos Position) (bool, *GameState, error) {
+	return e.CheckPuzzleAnswerAs(gameID, pos, "")
+}
+
+// CheckPuzzleAnswerAs checks an answer for an authenticated principal, with
+// the seat rules of MakeMoveAs: the side to move must be free or bound to
+// them, and a correct answer binds it
+func (e *Engine) CheckPuzzleAnswerAs(gameID string, pos Position, principal string) (bool, *GameState, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	game, exists := e.games[gameID]
+	if !exists {
+		return false, nil, fmt.Errorf("game with ID %s not found", gameID)
+	}
+	if game.Puzzle == nil {
+		return false, nil, fmt.Errorf("game %s is not a puzzle", gameID)
+	}
+	if game.PuzzleSolved {
+		return false, nil, fmt.Errorf("puzzle in game %s is already solved", gameID)
+	}
+	if len(game.Moves) > 0 {
+		// Solutions only apply to the puzzle's own position
+		return false, nil, fmt.Errorf("game %s has moved on from the puzzle position; reset it to try again", gameID)
+	}
+	if pos.Row < 0 || pos.Row > 2 || pos.Col < 0 || pos.Col > 2 || !game.Board.IsEmpty(pos) {
+		return false, nil, fmt.Errorf("position %s is not a legal move", pos)
+	}
+	if err := checkSeat(game, game.CurrentPlayer, principal); err != nil {
+		return false, nil, err
+	}
+
+	game.PuzzleAttempts++
+	if !game.Puzzle.IsSolution(pos) {
+		return false, game.Clone(), nil
+	}
+
+	player := game.CurrentPlayer
+	if err := e.applyMove(game, pos, player); err != nil {
+		return false, nil, err
+	}
+	bindSeat(game, player, principal)
+	game.PuzzleSolved = true
+	e.publishMove(game)
+	return true, game.Clone(), nil
+}
@@ -0,0 +1,107 @@
+package game
+
+import "testing"
+
+func TestPuzzles(t *testing.T) {
+	all := Puzzles()
+	if len(all) == 0 {
+		t.Fatal("Expected generated puzzles")
+	}
+
+	counts := make(map[PuzzleKind]int)
+	for _, puzzle := range all {
+		counts[puzzle.Kind]++
+
+		board, toMove, err := ParseFEN(puzzle.FEN)
+		if err != nil {
+			t.Fatalf("Puzzle %s has invalid position: %v", puzzle.ID, err)
+		}
+		if puzzle.Difficulty < MinPuzzleDifficulty || puzzle.Difficulty > MaxPuzzleDifficulty {
+			t.Errorf("Puzzle %s has difficulty %d out of range", puzzle.ID, puzzle.Difficulty)
+		}
+
+		eval := Evaluate(board, toMove)
+		switch puzzle.Kind {
+		case PuzzleWinInN:
+			if eval.Outcome != OutcomeWin || (eval.Plies+1)/2 != puzzle.WinIn {
+				t.Errorf("Puzzle %s claims win in %d, solver says %s", puzzle.ID, puzzle.WinIn, eval)
+			}
+		case PuzzleOnlyDraw:
+			if eval.Outcome != OutcomeDraw || len(puzzle.Solutions) != 1 {
+				t.Errorf("Puzzle %s should have exactly one drawing move", puzzle.ID)
+			}
+		case PuzzleBlockFork:
+			if len(board.ForkSquares(toMove.Opponent())) == 0 {
+				t.Errorf("Puzzle %s has no fork to block", puzzle.ID)
+			}
+		}
+		if len(puzzle.Solutions) == 0 || len(puzzle.Solutions) == len(board.EmptyPositions()) {
+			t.Errorf("Puzzle %s should have some but not all moves as solutions", puzzle.ID)
+		}
+	}
+
+	for _, kind := range PuzzleKinds {
+		if counts[kind] == 0 {
+			t.Errorf("Expected puzzles of kind %s", kind)
+		}
+	}
+}
+
+func TestCheckPuzzleAnswer(t *testing.T) {
+	engine := NewEngine()
+
+	puzzle, err := RandomPuzzle(PuzzleOnlyDraw, 0)
+	if err != nil {
+		t.Fatalf("RandomPuzzle() failed: %v", err)
+	}
+	game, err := engine.CreatePuzzleGame("puzzle", puzzle)
+	if err != nil {
+		t.Fatalf("CreatePuzzleGame() failed: %v", err)
+	}
+	if game.Variant != VariantPuzzle || game.FEN() != puzzle.FEN {
+		t.Fatalf("Puzzle game should start at the puzzle position, got %s", game.FEN())
+	}
+
+	var wrong Position
+	for _, pos := range game.Board.EmptyPositions() {
+		if !puzzle.IsSolution(pos) {
+			wrong = pos
+			break
+		}
+	}
+	correct, game, err := engine.CheckPuzzleAnswer("puzzle", wrong)
+	if err != nil || correct {
+		t.Fatalf("Expected a wrong answer for %s, got correct=%v err=%v", wrong, correct, err)
+	}
+	if game.MoveCount != 0 || game.PuzzleAttempts != 1 {
+		t.Error("Wrong answers should count an attempt without moving")
+	}
+
+	correct, game, err = engine.CheckPuzzleAnswer("puzzle", puzzle.Solutions[0])
+	if err != nil || !correct {
+		t.Fatalf("Expected a correct answer, got correct=%v err=%v", correct, err)
+	}
+	if !game.PuzzleSolved || game.MoveCount != 1 {
+		t.Error("Correct answer should be played and solve the puzzle")
+	}
+
+	if _, _, err := engine.CheckPuzzleAnswer("puzzle", wrong); err == nil {
+		t.Error("Should reject answers once the puzzle is solved")
+	}
+
+	// Once a move is played, the puzzle's solutions no longer apply
+	played, _ := engine.CreatePuzzleGame("played", puzzle)
+	engine.MakeMove("played", wrong, played.CurrentPlayer)
+	if _, _, err := engine.CheckPuzzleAnswer("played", puzzle.Solutions[0]); err == nil {
+		t.Error("Should reject answers after the game has left the puzzle position")
+	}
+	engine.ResetGame("played")
+	if correct, _, err := engine.CheckPuzzleAnswer("played", puzzle.Solutions[0]); err != nil || !correct {
+		t.Errorf("Expected answers to be accepted after a reset, got correct=%v err=%v", correct, err)
+	}
+
+	engine.CreateGame("plain")
+	if _, _, err := engine.CheckPuzzleAnswer("plain", wrong); err == nil {
+		t.Error("Should reject answers for a non-puzzle game")
+	}
+}
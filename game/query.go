@@ -0,0 +1,210 @@
+package game
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortField selects the timestamp games are ordered by when listed
+type SortField string
+
+const (
+	SortByCreated SortField = "created"
+	SortByUpdated SortField = "updated"
+)
+
+const (
+	// DefaultPageSize is used when a GameFilter does not set a limit
+	DefaultPageSize = 20
+	// MaxPageSize caps the number of games returned in a single page
+	MaxPageSize = 100
+)
+
+// GameFilter selects, orders and paginates games for QueryGames.
+// Zero values mean "no filter" and the defaults described on each field.
+type GameFilter struct {
+	Status       GameStatus
+	Variant      Variant
+	Player       string    // Matches either participant name, case-insensitively
+	CreatedAfter time.Time // Only games created strictly after this time
+	SortBy       SortField // Defaults to SortByCreated
+	Ascending    bool      // Defaults to newest first
+	Limit        int       // Defaults to DefaultPageSize, capped at MaxPageSize
+	Cursor       string    // NextCursor from a previous page
+}
+
+// GameSummary is a compact description of a game for listings
+type GameSummary struct {
+	GameID    string
+	Status    GameStatus
+	Variant   Variant
+	Winner    Player
+	MoveCount int
+	Players   map[Player]string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GamePage is one page of QueryGames results
+type GamePage struct {
+	Games      []GameSummary
+	Total      int    // Number of games matching the filter across all pages
+	NextCursor string // Empty when there are no further pages
+}
+
+// Summary returns a GameSummary for the game
+func (g *GameState) Summary() GameSummary {
+	players := make(map[Player]string, len(g.Players))
+	for player, name := range g.Players {
+		players[player] = name
+	}
+	return GameSummary{
+		GameID:    g.GameID,
+		Status:    g.Status,
+		Variant:   g.Variant,
+		Winner:    g.Winner,
+		MoveCount: g.MoveCount,
+		Players:   players,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}
+}
+
+// matches reports whether the game passes every filter criterion
+func (f GameFilter) matches(g *GameState) bool {
+	if f.Status != "" && g.Status != f.Status {
+		return false
+	}
+	if f.Variant != "" && g.Variant != f.Variant {
+		return false
+	}
+	if f.Player != "" {
+		found := false
+		for _, name := range g.Players {
+			if strings.EqualFold(name, f.Player) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.CreatedAfter.IsZero() && !g.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	return true
+}
+
+// sortKey returns the timestamp the filter orders games by
+func (f GameFilter) sortKey(s GameSummary) time.Time {
+	if f.SortBy == SortByUpdated {
+		return s.UpdatedAt
+	}
+	return s.CreatedAt
+}
+
+// before reports whether a sorts ahead of b; game IDs break timestamp ties
+func (f GameFilter) before(a, b GameSummary) bool {
+	ka, kb := f.sortKey(a).UnixNano(), f.sortKey(b).UnixNano()
+	if ka == kb {
+		if f.Ascending {
+			return a.GameID < b.GameID
+		}
+		return a.GameID > b.GameID
+	}
+	if f.Ascending {
+		return ka < kb
+	}
+	return ka > kb
+}
+
+// QueryGames returns the page of games matching the filter
+func (e *Engine) QueryGames(filter GameFilter) (GamePage, error) {
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = SortByCreated
+	case SortByCreated, SortByUpdated:
+	default:
+		return GamePage{}, fmt.Errorf("unknown sort field %q (supported: created, updated)", filter.SortBy)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+
+	var after *GameSummary
+	if filter.Cursor != "" {
+		key, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return GamePage{}, err
+		}
+		after = &GameSummary{GameID: id, CreatedAt: time.Unix(0, key), UpdatedAt: time.Unix(0, key)}
+	}
+
+	e.mutex.RLock()
+	matched := make([]GameSummary, 0)
+	for _, g := range e.games {
+		if filter.matches(g) {
+			matched = append(matched, g.Summary())
+		}
+	}
+	e.mutex.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return filter.before(matched[i], matched[j])
+	})
+
+	// Keyset pagination: resume strictly after the cursor's position so that
+	// games created between page requests don't shift or duplicate entries
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matched), func(i int) bool {
+			return filter.before(*after, matched[i])
+		})
+	}
+
+	end := start + filter.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	page := GamePage{
+		Games: matched[start:end],
+		Total: len(matched),
+	}
+	if end < len(matched) {
+		last := matched[end-1]
+		page.NextCursor = encodeCursor(filter.sortKey(last).UnixNano(), last.GameID)
+	}
+	return page, nil
+}
+
+// encodeCursor builds an opaque pagination cursor from a sort key and game ID
+func encodeCursor(key int64, gameID string) string {
+	raw := strconv.FormatInt(key, 10) + ":" + gameID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor reverses encodeCursor
+func decodeCursor(cursor string) (int64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid cursor")
+	}
+	keyStr, gameID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, "", fmt.Errorf("invalid cursor")
+	}
+	key, err := strconv.ParseInt(keyStr, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid cursor")
+	}
+	return key, gameID, nil
+}
@@ -0,0 +1,340 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Game records use a PGN-like text format (TGN, "tic-tac-toe game notation"):
+//
+//	[Game "game-a1b2c3d4"]
+//	[Date "2025.07.01"]
+//	[Variant "standard"]
+//	[X "alice"]
+//	[O "claude"]
+//	[Result "0-1"]
+//
+//	1. B2 A1 2. C2 A2 3. C1 A3 0-1
+//
+// Tag pairs come first, one per line, followed by a blank line and the move
+// text. Moves use A1-C3 notation with X's and O's moves grouped under a move
+// number, and the move text ends with the result token. Text inside braces is
+// treated as a comment. Games started from a set-up position carry a FEN tag
+// with the starting position in compact notation (see ParseFEN).
+
+// Result tokens used in the Result tag and at the end of the move text
+const (
+	ResultXWins   = "1-0"
+	ResultOWins   = "0-1"
+	ResultDraw    = "1/2-1/2"
+	ResultOngoing = "*"
+)
+
+// Standard tag names, written in this order before any other tags
+const (
+	TagGame    = "Game"
+	TagDate    = "Date"
+	TagVariant = "Variant"
+	TagFEN     = "FEN" // Starting position of setup games
+	TagX       = "X"
+	TagO       = "O"
+	TagResult  = "Result"
+)
+
+var standardTags = []string{TagGame, TagDate, TagVariant, TagFEN, TagX, TagO, TagResult}
+
+// recordDateFormat is the layout of the Date tag
+const recordDateFormat = "2006.01.02"
+
+// Record is a parsed or exported game record
+type Record struct {
+	Tags  map[string]string
+	Moves []Position
+}
+
+// NewRecord builds a record describing the given game
+func NewRecord(g *GameState) *Record {
+	r := &Record{
+		Tags: map[string]string{
+			TagGame:    g.GameID,
+			TagDate:    g.CreatedAt.UTC().Format(recordDateFormat),
+			TagVariant: string(g.Variant),
+			TagX:       "?",
+			TagO:       "?",
+			TagResult:  ResultFor(g),
+		},
+		Moves: make([]Position, len(g.Moves)),
+	}
+	if g.StartFEN != "" {
+		r.Tags[TagFEN] = g.StartFEN
+	}
+	if name := g.Players[PlayerX]; name != "" {
+		r.Tags[TagX] = name
+	}
+	if name := g.Players[PlayerO]; name != "" {
+		r.Tags[TagO] = name
+	}
+	for i, move := range g.Moves {
+		r.Moves[i] = move.Position
+	}
+	return r
+}
+
+// ResultFor returns the result token for a game's current status
+func ResultFor(g *GameState) string {
+	switch g.Status {
+	case StatusWon:
+		if g.Winner == PlayerX {
+			return ResultXWins
+		}
+		return ResultOWins
+	case StatusDraw:
+		return ResultDraw
+	}
+	return ResultOngoing
+}
+
+// Result returns the record's result token, defaulting to ongoing
+func (r *Record) Result() string {
+	if result, ok := r.Tags[TagResult]; ok && isResultToken(result) {
+		return result
+	}
+	return ResultOngoing
+}
+
+// String serializes the record in game record notation
+func (r *Record) String() string {
+	var sb strings.Builder
+
+	written := make(map[string]bool, len(r.Tags))
+	for _, tag := range standardTags {
+		if value, ok := r.Tags[tag]; ok {
+			writeTag(&sb, tag, value)
+			written[tag] = true
+		}
+	}
+
+	extra := make([]string, 0, len(r.Tags))
+	for tag := range r.Tags {
+		if !written[tag] {
+			extra = append(extra, tag)
+		}
+	}
+	sort.Strings(extra)
+	for _, tag := range extra {
+		writeTag(&sb, tag, r.Tags[tag])
+	}
+
+	sb.WriteString("\n")
+
+	// Setup positions with O to move start with an elided X move ("1... B2")
+	offset := 0
+	if fen := strings.Fields(r.Tags[TagFEN]); len(fen) == 2 && strings.EqualFold(fen[1], "o") {
+		offset = 1
+	}
+	for i, pos := range r.Moves {
+		ply := i + offset
+		if i == 0 && offset == 1 {
+			sb.WriteString("1... ")
+		} else if ply%2 == 0 {
+			fmt.Fprintf(&sb, "%d. ", ply/2+1)
+		}
+		sb.WriteString(pos.String())
+		sb.WriteString(" ")
+	}
+	sb.WriteString(r.Result())
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// writeTag writes a single tag pair line. The value is quoted with Go
+// escapes, so newlines and other control characters survive parseTag.
+func writeTag(sb *strings.Builder, tag, value string) {
+	fmt.Fprintf(sb, "[%s %s]\n", tag, strconv.Quote(value))
+}
+
+// ParseRecord parses a game record. It checks the notation only; use
+// Engine.ImportGame to also verify that the moves are legal.
+func ParseRecord(text string) (*Record, error) {
+	r := &Record{Tags: make(map[string]string)}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			break
+		}
+		tag, value, err := parseTag(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		r.Tags[tag] = value
+	}
+
+	movetext := stripComments(strings.Join(lines[i:], " "))
+	result := ""
+	for _, token := range strings.Fields(movetext) {
+		if result != "" {
+			return nil, fmt.Errorf("unexpected %q after result %s", token, result)
+		}
+		if isResultToken(token) {
+			result = token
+			continue
+		}
+
+		// Move numbers may be attached to the move ("1.B2") or stand alone ("1.")
+		if dot := strings.LastIndex(token, "."); dot >= 0 {
+			if _, err := strconv.Atoi(strings.TrimRight(token[:dot], ".")); err != nil {
+				return nil, fmt.Errorf("invalid move number %q", token)
+			}
+			token = token[dot+1:]
+			if token == "" {
+				continue
+			}
+		}
+
+		pos, err := ParsePosition(strings.ToUpper(token))
+		if err != nil {
+			return nil, fmt.Errorf("invalid move %q: %v", token, err)
+		}
+		r.Moves = append(r.Moves, pos)
+	}
+
+	if tagResult, ok := r.Tags[TagResult]; ok {
+		if !isResultToken(tagResult) {
+			return nil, fmt.Errorf("invalid Result tag %q", tagResult)
+		}
+		if result != "" && result != tagResult {
+			return nil, fmt.Errorf("move text result %s does not match Result tag %s", result, tagResult)
+		}
+	} else if result != "" {
+		r.Tags[TagResult] = result
+	}
+
+	return r, nil
+}
+
+// parseTag parses a `[Name "value"]` tag pair line
+func parseTag(line string) (string, string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("tag pair must end with ]")
+	}
+	inner := strings.TrimSpace(line[1 : len(line)-1])
+	name, quoted, ok := strings.Cut(inner, " ")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("tag pair must have a name and a quoted value")
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return "", "", fmt.Errorf("tag %s has an invalid quoted value", name)
+	}
+	return name, value, nil
+}
+
+// stripComments removes {brace} comments from move text
+func stripComments(text string) string {
+	var sb strings.Builder
+	depth := 0
+	for _, c := range text {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+			sb.WriteRune(' ')
+		case depth == 0:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// isResultToken reports whether s is one of the result tokens
+func isResultToken(s string) bool {
+	switch s {
+	case ResultXWins, ResultOWins, ResultDraw, ResultOngoing:
+		return true
+	}
+	return false
+}
+
+// ExportGame returns the game record for a game
+func (e *Engine) ExportGame(gameID string) (string, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	game, exists := e.games[gameID]
+	if !exists {
+		return "", fmt.Errorf("game with ID %s not found", gameID)
+	}
+
+	return NewRecord(game).String(), nil
+}
+
+// ImportGame replays a game record into a new game. The game ID defaults to
+// the record's Game tag; importing over an existing game is an error. The
+// game's creation time comes from the Date tag when it has one. Importing
+// publishes only GameCreated, even for a finished game: its result was
+// reached elsewhere, so GameEnded subscribers should not count it again.
+func (e *Engine) ImportGame(gameID string, record *Record) (*GameState, error) {
+	return e.ImportGameAs(gameID, record, "")
+}
+
+// ImportGameAs imports a game owned by an authenticated principal
+func (e *Engine) ImportGameAs(gameID string, record *Record, owner string) (*GameState, error) {
+	if gameID == "" {
+		gameID = record.Tags[TagGame]
+	}
+	if gameID == "" {
+		return nil, fmt.Errorf("record has no Game tag and no game ID was given")
+	}
+
+	game := NewGame(gameID)
+	game.Owner = owner
+	if fen := record.Tags[TagFEN]; fen != "" {
+		if err := e.setupPosition(game, fen); err != nil {
+			return nil, err
+		}
+		game.Variant = VariantSetup
+	}
+	if variant := record.Tags[TagVariant]; variant != "" {
+		game.Variant = Variant(variant)
+	}
+	for player, tag := range map[Player]string{PlayerX: TagX, PlayerO: TagO} {
+		if name := record.Tags[tag]; name != "" && name != "?" {
+			game.Players[player] = name
+		}
+	}
+	if date, err := time.Parse(recordDateFormat, record.Tags[TagDate]); err == nil {
+		game.CreatedAt = date
+	}
+
+	for i, pos := range record.Moves {
+		if err := e.applyMove(game, pos, game.CurrentPlayer); err != nil {
+			return nil, fmt.Errorf("move %d (%s): %v", i+1, pos, err)
+		}
+	}
+
+	if result := record.Result(); result != ResultOngoing && result != ResultFor(game) {
+		return nil, fmt.Errorf("moves lead to result %s but record claims %s", ResultFor(game), result)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, exists := e.games[gameID]; exists {
+		return nil, fmt.Errorf("game with ID %s %w", gameID, ErrGameExists)
+	}
+	game.UpdatedAt = time.Now()
+	e.games[gameID] = game
+	e.publish(EventGameCreated, game, nil)
+	return game.Clone(), nil
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGameWithOptions("record-game", GameOptions{
+		Players: map[Player]string{PlayerX: "alice", PlayerO: `bot "one"`},
+	})
+
+	for i, posStr := range []string{"B2", "A1", "C2", "A2", "C1", "A3"} {
+		pos, _ := ParsePosition(posStr)
+		player := PlayerX
+		if i%2 == 1 {
+			player = PlayerO
+		}
+		if _, err := engine.MakeMove("record-game", pos, player); err != nil {
+			t.Fatalf("Move %d failed: %v", i, err)
+		}
+	}
+
+	text, err := engine.ExportGame("record-game")
+	if err != nil {
+		t.Fatalf("ExportGame() failed: %v", err)
+	}
+	if !strings.Contains(text, `[Result "0-1"]`) {
+		t.Errorf("Record should contain O's win, got:\n%s", text)
+	}
+	if !strings.Contains(text, "1. B2 A1 2. C2 A2 3. C1 A3 0-1") {
+		t.Errorf("Record should contain the move list, got:\n%s", text)
+	}
+
+	record, err := ParseRecord(text)
+	if err != nil {
+		t.Fatalf("ParseRecord() failed: %v", err)
+	}
+	if record.Tags[TagO] != `bot "one"` {
+		t.Errorf("Expected escaped player name to round trip, got %q", record.Tags[TagO])
+	}
+
+	imported, err := engine.ImportGame("copy", record)
+	if err != nil {
+		t.Fatalf("ImportGame() failed: %v", err)
+	}
+	if imported.Status != StatusWon || imported.Winner != PlayerO || imported.MoveCount != 6 {
+		t.Errorf("Imported game has wrong outcome: %+v", imported)
+	}
+	if imported.Players[PlayerX] != "alice" {
+		t.Errorf("Expected player X to be alice, got %q", imported.Players[PlayerX])
+	}
+
+	record.Tags[TagDate] = "2024.03.15"
+	dated, err := engine.ImportGame("dated", record)
+	if err != nil {
+		t.Fatalf("ImportGame() with a Date tag failed: %v", err)
+	}
+	if want := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC); !dated.CreatedAt.Equal(want) {
+		t.Errorf("Expected CreatedAt from the Date tag, got %v", dated.CreatedAt)
+	}
+
+	if _, err := engine.ImportGame("copy", record); err == nil {
+		t.Error("Should reject importing over an existing game")
+	}
+}
+
+func TestRecordQuotesControlCharacters(t *testing.T) {
+	engine := NewEngine()
+	name := "line one\nline \"two\"\t\\end"
+	engine.CreateGameWithOptions("control", GameOptions{
+		Players: map[Player]string{PlayerX: name},
+	})
+
+	text, err := engine.ExportGame("control")
+	if err != nil {
+		t.Fatalf("ExportGame() failed: %v", err)
+	}
+	record, err := ParseRecord(text)
+	if err != nil {
+		t.Fatalf("ParseRecord() failed: %v\n%s", err, text)
+	}
+	if record.Tags[TagX] != name {
+		t.Errorf("Expected player name %q to round trip, got %q", name, record.Tags[TagX])
+	}
+}
+
+func TestParseRecordErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+	}{
+		{"bad tag", "[Game game-1]\n\n1. B2 *"},
+		{"bad move", "1. B2 D4 *"},
+		{"bad move number", "x. B2 *"},
+		{"moves after result", "1. B2 A1 1-0 C3"},
+		{"result mismatch", "[Result \"1-0\"]\n\n1. B2 A1 0-1"},
+	}
+
+	for _, tc := range testCases {
+		if _, err := ParseRecord(tc.text); err == nil {
+			t.Errorf("%s: expected error for %q", tc.name, tc.text)
+		}
+	}
+
+	record, err := ParseRecord("1.B2 {center} A1 2. a2 *")
+	if err != nil {
+		t.Fatalf("ParseRecord() failed on compact move text: %v", err)
+	}
+	if len(record.Moves) != 3 || record.Moves[2] != (Position{Row: 1, Col: 0}) {
+		t.Errorf("Unexpected moves: %+v", record.Moves)
+	}
+}
+
+func TestImportGameValidatesMoves(t *testing.T) {
+	engine := NewEngine()
+
+	record, _ := ParseRecord("[Game \"dup\"]\n\n1. B2 B2 *")
+	if _, err := engine.ImportGame("", record); err == nil {
+		t.Error("Should reject a record that plays an occupied square")
+	}
+
+	record, _ = ParseRecord("1. A1 A2 2. B1 B2 3. C1 *")
+	record.Tags[TagResult] = ResultOWins
+	if _, err := engine.ImportGame("wrong-result", record); err == nil {
+		t.Error("Should reject a record whose result contradicts its moves")
+	}
+
+	if len(engine.ListGames()) != 0 {
+		t.Error("Failed imports should not create games")
+	}
+}
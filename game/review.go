@@ -0,0 +1,182 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MoveQuality classifies a move by how much value it gave away
+type MoveQuality string
+
+const (
+	QualityBest       MoveQuality = "best"       // Kept the best available evaluation
+	QualityInaccuracy MoveQuality = "inaccuracy" // Kept the outcome but won slower or lost faster
+	QualityBlunder    MoveQuality = "blunder"    // Worsened the outcome, e.g. a won game into a draw
+)
+
+// MoveReview is the assessment of one move in a finished game
+type MoveReview struct {
+	Number    int         `json:"number"` // 1-based index into the game's moves
+	Player    Player      `json:"player"`
+	Position  Position    `json:"position"`
+	Quality   MoveQuality `json:"quality"`
+	Before    Evaluation  `json:"before"` // Best value available to the mover
+	After     Evaluation  `json:"after"`  // Value of the move played, for the mover
+	BestMoves []Position  `json:"best_moves"`
+}
+
+// PlayerReview totals move quality for one side
+type PlayerReview struct {
+	Moves        int `json:"moves"`
+	Best         int `json:"best"`
+	Inaccuracies int `json:"inaccuracies"`
+	Blunders     int `json:"blunders"`
+}
+
+// ErrorRate returns the fraction of moves that were not best
+func (p PlayerReview) ErrorRate() float64 {
+	if p.Moves == 0 {
+		return 0
+	}
+	return float64(p.Inaccuracies+p.Blunders) / float64(p.Moves)
+}
+
+// Review is a move-by-move assessment of a finished game
+type Review struct {
+	GameID  string                  `json:"game_id"`
+	Result  string                  `json:"result"`
+	Moves   []MoveReview            `json:"moves"`
+	Players map[Player]PlayerReview `json:"players"`
+	// DecisiveMove is the Number of the blunder that settled the final
+	// result, or 0 if the result was already determined before any mistake
+	DecisiveMove int `json:"decisive_move"`
+}
+
+// ReviewGame classifies every move of a finished game
+func (e *Engine) ReviewGame(gameID string) (*Review, error) {
+	e.mutex.RLock()
+	game, exists := e.games[gameID]
+	if !exists {
+		e.mutex.RUnlock()
+		return nil, fmt.Errorf("game with ID %s not found", gameID)
+	}
+	if !game.IsGameOver() {
+		e.mutex.RUnlock()
+		return nil, fmt.Errorf("game %s is still in progress", gameID)
+	}
+	startFEN := game.StartFEN
+	moves := append([]Move(nil), game.Moves...)
+	result := ResultFor(game)
+	e.mutex.RUnlock()
+
+	board, toMove := NewBoard(), PlayerX
+	if startFEN != "" {
+		var err error
+		if board, toMove, err = ParseFEN(startFEN); err != nil {
+			return nil, err
+		}
+	}
+
+	review := &Review{
+		GameID:  gameID,
+		Result:  result,
+		Moves:   make([]MoveReview, 0, len(moves)),
+		Players: map[Player]PlayerReview{PlayerX: {}, PlayerO: {}},
+	}
+
+	// The decisive blunder is the last one that turned the theoretical
+	// result into the final result, provided no later move changed it back
+	final := resultOutcomeForX(result)
+	for i, move := range moves {
+		before := Evaluate(board, toMove)
+		best := BestMoves(board, toMove)
+
+		board.Set(move.Position, move.Player)
+		after := evaluationFromScore(backUp(Evaluate(board, toMove.Opponent()).score()))
+
+		quality := QualityBest
+		switch {
+		case after.Outcome < before.Outcome:
+			quality = QualityBlunder
+		case before.Better(after):
+			quality = QualityInaccuracy
+		}
+
+		review.Moves = append(review.Moves, MoveReview{
+			Number:    i + 1,
+			Player:    move.Player,
+			Position:  move.Position,
+			Quality:   quality,
+			Before:    before,
+			After:     after,
+			BestMoves: best,
+		})
+
+		stats := review.Players[move.Player]
+		stats.Moves++
+		switch quality {
+		case QualityBest:
+			stats.Best++
+		case QualityInaccuracy:
+			stats.Inaccuracies++
+		case QualityBlunder:
+			stats.Blunders++
+		}
+		review.Players[move.Player] = stats
+
+		outcomeForX := after.Outcome
+		if move.Player == PlayerO {
+			outcomeForX = -outcomeForX
+		}
+		if quality == QualityBlunder && outcomeForX == final {
+			review.DecisiveMove = i + 1
+		} else if outcomeForX != final {
+			review.DecisiveMove = 0
+		}
+
+		toMove = toMove.Opponent()
+	}
+
+	return review, nil
+}
+
+// resultOutcomeForX converts a result token into an outcome for X
+func resultOutcomeForX(result string) Outcome {
+	switch result {
+	case ResultXWins:
+		return OutcomeWin
+	case ResultOWins:
+		return OutcomeLoss
+	}
+	return OutcomeDraw
+}
+
+// String renders the review as an annotated move list with totals
+func (r *Review) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Review of game %s (result %s)\n", r.GameID, r.Result)
+
+	for _, move := range r.Moves {
+		fmt.Fprintf(&sb, "%d. %s %s - %s", move.Number, move.Player, move.Position, move.Quality)
+		if move.Quality != QualityBest {
+			fmt.Fprintf(&sb, " (%s -> %s; best: %s)", move.Before, move.After, joinPositions(move.BestMoves))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, player := range []Player{PlayerX, PlayerO} {
+		stats := r.Players[player]
+		fmt.Fprintf(&sb, "\n%s: %d moves, %d best, %d inaccuracies, %d blunders (error rate %.0f%%)",
+			player, stats.Moves, stats.Best, stats.Inaccuracies, stats.Blunders, 100*stats.ErrorRate())
+	}
+
+	if r.DecisiveMove > 0 {
+		move := r.Moves[r.DecisiveMove-1]
+		fmt.Fprintf(&sb, "\nDecisive mistake: move %d, %s at %s (%s -> %s)",
+			move.Number, move.Player, move.Position, move.Before, move.After)
+	} else {
+		sb.WriteString("\nDecisive mistake: none; the result matches perfect play")
+	}
+
+	return sb.String()
+}
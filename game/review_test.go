@@ -0,0 +1,70 @@
+package game
+
+import "testing"
+
+// playMoves plays alternating moves starting with the game's current player
+func playMoves(t *testing.T, engine *Engine, gameID string, moves ...string) {
+	t.Helper()
+	for _, move := range moves {
+		game, _ := engine.GetGame(gameID)
+		pos, _ := ParsePosition(move)
+		if _, err := engine.MakeMove(gameID, pos, game.CurrentPlayer); err != nil {
+			t.Fatalf("Move %s failed: %v", move, err)
+		}
+	}
+}
+
+func TestReviewGame(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGame("blunder")
+
+	// O's edge reply to the centre loses to X's corner attack
+	playMoves(t, engine, "blunder", "B2", "B1", "A1", "C3", "A3", "C1", "A2")
+
+	review, err := engine.ReviewGame("blunder")
+	if err != nil {
+		t.Fatalf("ReviewGame() failed: %v", err)
+	}
+	if review.Result != ResultXWins || len(review.Moves) != 7 {
+		t.Fatalf("Unexpected review: %+v", review)
+	}
+	if review.Moves[1].Quality != QualityBlunder {
+		t.Errorf("O's B1 should be a blunder, got %s", review.Moves[1].Quality)
+	}
+	if review.DecisiveMove != 2 {
+		t.Errorf("Expected move 2 to be decisive, got %d", review.DecisiveMove)
+	}
+	if stats := review.Players[PlayerX]; stats.Moves != 4 || stats.Best != 4 {
+		t.Errorf("X should have played 4 best moves, got %+v", stats)
+	}
+	if stats := review.Players[PlayerO]; stats.Blunders != 1 || stats.ErrorRate() == 0 {
+		t.Errorf("O should have one blunder, got %+v", stats)
+	}
+}
+
+func TestReviewGameInaccuracy(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGameWithOptions("slow", GameOptions{FEN: ".../..X/OOX x"})
+
+	// X wins in 3 plies via A1 instead of immediately at C1
+	playMoves(t, engine, "slow", "A1", "C1", "B2")
+
+	review, err := engine.ReviewGame("slow")
+	if err != nil {
+		t.Fatalf("ReviewGame() failed: %v", err)
+	}
+	if review.Moves[0].Quality != QualityInaccuracy {
+		t.Errorf("A1 should be an inaccuracy, got %s", review.Moves[0].Quality)
+	}
+	if len(review.Moves[0].BestMoves) != 1 || review.Moves[0].BestMoves[0].String() != "C1" {
+		t.Errorf("Best move should be C1, got %v", review.Moves[0].BestMoves)
+	}
+	if review.DecisiveMove != 0 {
+		t.Errorf("X was winning from the start, got decisive move %d", review.DecisiveMove)
+	}
+
+	engine.CreateGame("ongoing")
+	if _, err := engine.ReviewGame("ongoing"); err == nil {
+		t.Error("Should refuse to review a game in progress")
+	}
+}
@@ -0,0 +1,313 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulationConfig describes a batch of self-play games
+type SimulationConfig struct {
+	// Strategies are the names of the two contestants. The first plays X in
+	// even-numbered games; with Alternate set, the sides swap every game.
+	Strategies [2]string
+	Games      int
+	Workers    int    // Defaults to GOMAXPROCS
+	Seed       uint64 // Runs with the same seed and worker count are reproducible
+	Alternate  bool
+}
+
+// Tally counts game outcomes
+type Tally struct {
+	Wins   int `json:"wins"`
+	Draws  int `json:"draws"`
+	Losses int `json:"losses"`
+}
+
+// Games returns the number of games counted
+func (t Tally) Games() int {
+	return t.Wins + t.Draws + t.Losses
+}
+
+// Rates returns the win, draw and loss fractions
+func (t Tally) Rates() (win, draw, loss float64) {
+	n := float64(t.Games())
+	if n == 0 {
+		return 0, 0, 0
+	}
+	return float64(t.Wins) / n, float64(t.Draws) / n, float64(t.Losses) / n
+}
+
+func (t *Tally) add(o Tally) {
+	t.Wins += o.Wins
+	t.Draws += o.Draws
+	t.Losses += o.Losses
+}
+
+func (t *Tally) record(outcome Outcome) {
+	switch outcome {
+	case OutcomeWin:
+		t.Wins++
+	case OutcomeLoss:
+		t.Losses++
+	default:
+		t.Draws++
+	}
+}
+
+// Interval is a confidence interval for a proportion
+type Interval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// WilsonInterval returns the Wilson score interval for successes out of n
+// trials at the given z-score (1.96 for 95%)
+func WilsonInterval(successes, n int, z float64) Interval {
+	if n == 0 {
+		return Interval{0, 1}
+	}
+	p := float64(successes) / float64(n)
+	nf := float64(n)
+	denom := 1 + z*z/nf
+	center := (p + z*z/(2*nf)) / denom
+	margin := z * math.Sqrt(p*(1-p)/nf+z*z/(4*nf*nf)) / denom
+	return Interval{Low: math.Max(0, center-margin), High: math.Min(1, center+margin)}
+}
+
+// StrategyResult is one contestant's record, overall and by side
+type StrategyResult struct {
+	Name    string   `json:"name"`
+	Total   Tally    `json:"total"`
+	AsX     Tally    `json:"as_x"`
+	AsO     Tally    `json:"as_o"`
+	WinRate Interval `json:"win_rate_95"`
+	// NonLossRate is the interval for wins plus draws
+	NonLossRate Interval `json:"non_loss_rate_95"`
+}
+
+// SimulationResult aggregates a batch of self-play games
+type SimulationResult struct {
+	Games      int                     `json:"games"`
+	XWins      int                     `json:"x_wins"`
+	OWins      int                     `json:"o_wins"`
+	Draws      int                     `json:"draws"`
+	TotalPlies int                     `json:"total_plies"`
+	Strategies [2]StrategyResult       `json:"strategies"`
+	FirstMoves map[Position]*FirstMove `json:"first_moves"`
+	Duration   time.Duration           `json:"duration_ns"`
+}
+
+// FirstMove counts games by X's opening square, from X's point of view
+type FirstMove struct {
+	Tally
+	Plies int `json:"plies"`
+}
+
+// AverageLength returns the mean number of moves per game
+func (r *SimulationResult) AverageLength() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.TotalPlies) / float64(r.Games)
+}
+
+// simulationShard is a worker's private tally, merged at the end
+type simulationShard struct {
+	xWins, oWins, draws, plies int
+	sides                      [2][2]Tally // [contestant][0 as X, 1 as O]
+	firstMoves                 map[Position]*FirstMove
+}
+
+// Simulate plays cfg.Games games between two strategies across worker
+// goroutines. It stops early, returning the partial result and the context's
+// error, if ctx is cancelled.
+func Simulate(ctx context.Context, cfg SimulationConfig) (*SimulationResult, error) {
+	if cfg.Games <= 0 {
+		return nil, fmt.Errorf("games must be positive")
+	}
+	for _, name := range cfg.Strategies {
+		if _, err := NewStrategy(name); err != nil {
+			return nil, err
+		}
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, cfg.Games)
+
+	start := time.Now()
+	shards := make([]simulationShard, workers)
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		wg.Add(1)
+		go func(shard *simulationShard, seed uint64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(cfg.Seed, seed))
+			// Errors were ruled out above
+			a, _ := NewStrategy(cfg.Strategies[0])
+			b, _ := NewStrategy(cfg.Strategies[1])
+			contestants := [2]Strategy{a, b}
+			shard.firstMoves = make(map[Position]*FirstMove)
+
+			// Games are striped across workers so each worker's sequence,
+			// and therefore the whole run, depends only on the seed
+			for i := int(seed); i < cfg.Games; i += workers {
+				if ctx.Err() != nil {
+					return
+				}
+				xIndex := 0
+				if cfg.Alternate && i%2 == 1 {
+					xIndex = 1
+				}
+				shard.play(contestants, xIndex, rng)
+			}
+		}(&shards[w], uint64(w))
+	}
+	wg.Wait()
+
+	result := &SimulationResult{FirstMoves: make(map[Position]*FirstMove)}
+	var sides [2][2]Tally
+	for _, shard := range shards {
+		result.XWins += shard.xWins
+		result.OWins += shard.oWins
+		result.Draws += shard.draws
+		result.TotalPlies += shard.plies
+		for c := range sides {
+			sides[c][0].add(shard.sides[c][0])
+			sides[c][1].add(shard.sides[c][1])
+		}
+		for pos, fm := range shard.firstMoves {
+			merged, ok := result.FirstMoves[pos]
+			if !ok {
+				merged = &FirstMove{}
+				result.FirstMoves[pos] = merged
+			}
+			merged.add(fm.Tally)
+			merged.Plies += fm.Plies
+		}
+	}
+	result.Games = result.XWins + result.OWins + result.Draws
+	for c, name := range cfg.Strategies {
+		total := sides[c][0]
+		total.add(sides[c][1])
+		result.Strategies[c] = StrategyResult{
+			Name:        name,
+			Total:       total,
+			AsX:         sides[c][0],
+			AsO:         sides[c][1],
+			WinRate:     WilsonInterval(total.Wins, total.Games(), 1.96),
+			NonLossRate: WilsonInterval(total.Wins+total.Draws, total.Games(), 1.96),
+		}
+	}
+	result.Duration = time.Since(start)
+
+	return result, ctx.Err()
+}
+
+// play runs a single game with contestants[xIndex] as X and records it
+func (s *simulationShard) play(contestants [2]Strategy, xIndex int, rng *rand.Rand) {
+	var bb bitboard
+	player := PlayerX
+	var first Position
+	winner := Empty
+	plies := 0
+
+	for !bb.full() {
+		strategy := contestants[xIndex]
+		if player == PlayerO {
+			strategy = contestants[1-xIndex]
+		}
+		pos := strategy.ChooseMove(bb.board(), player, rng)
+		index := pos.Row*3 + pos.Col
+		if bb.empty()&(1<<index) == 0 {
+			panic(fmt.Sprintf("strategy %s chose occupied square %s", strategy.Name(), pos))
+		}
+		if plies == 0 {
+			first = pos
+		}
+		bb = bb.play(index, player)
+		plies++
+		if hasWin(bb.of(player)) {
+			winner = player
+			break
+		}
+		player = player.Opponent()
+	}
+
+	outcomeForX := OutcomeDraw
+	switch winner {
+	case PlayerX:
+		s.xWins++
+		outcomeForX = OutcomeWin
+	case PlayerO:
+		s.oWins++
+		outcomeForX = OutcomeLoss
+	default:
+		s.draws++
+	}
+	s.plies += plies
+
+	s.sides[xIndex][0].record(outcomeForX)
+	s.sides[1-xIndex][1].record(-outcomeForX)
+
+	fm, ok := s.firstMoves[first]
+	if !ok {
+		fm = &FirstMove{}
+		s.firstMoves[first] = fm
+	}
+	fm.record(outcomeForX)
+	fm.Plies += plies
+}
+
+// String renders the result as a plain-text report
+func (r *SimulationResult) String() string {
+	var sb strings.Builder
+	pct := func(n int) float64 { return 100 * float64(n) / float64(max(r.Games, 1)) }
+
+	fmt.Fprintf(&sb, "%d games in %s (%.0f games/s)\n", r.Games, r.Duration.Round(time.Millisecond),
+		float64(r.Games)/math.Max(r.Duration.Seconds(), 1e-9))
+	fmt.Fprintf(&sb, "X wins %d (%.1f%%), O wins %d (%.1f%%), draws %d (%.1f%%)\n",
+		r.XWins, pct(r.XWins), r.OWins, pct(r.OWins), r.Draws, pct(r.Draws))
+	fmt.Fprintf(&sb, "Average length: %.2f moves\n", r.AverageLength())
+
+	sb.WriteString("\nStrategies (95% Wilson intervals):\n")
+	for i, s := range r.Strategies {
+		win, draw, loss := s.Total.Rates()
+		fmt.Fprintf(&sb, "  %d. %-10s W/D/L %d/%d/%d (%.1f%%/%.1f%%/%.1f%%)  win [%.1f%%, %.1f%%]  non-loss [%.1f%%, %.1f%%]\n",
+			i+1, s.Name, s.Total.Wins, s.Total.Draws, s.Total.Losses, 100*win, 100*draw, 100*loss,
+			100*s.WinRate.Low, 100*s.WinRate.High, 100*s.NonLossRate.Low, 100*s.NonLossRate.High)
+		fmt.Fprintf(&sb, "     as X %d/%d/%d, as O %d/%d/%d\n",
+			s.AsX.Wins, s.AsX.Draws, s.AsX.Losses, s.AsO.Wins, s.AsO.Draws, s.AsO.Losses)
+	}
+
+	sb.WriteString("\nFirst moves (outcome for X):\n")
+	opening := make([]Position, 0, len(r.FirstMoves))
+	for pos := range r.FirstMoves {
+		opening = append(opening, pos)
+	}
+	sort.Slice(opening, func(i, j int) bool {
+		a, b := r.FirstMoves[opening[i]], r.FirstMoves[opening[j]]
+		if a.Games() != b.Games() {
+			return a.Games() > b.Games()
+		}
+		return opening[i].Row*3+opening[i].Col < opening[j].Row*3+opening[j].Col
+	})
+	for _, pos := range opening {
+		fm := r.FirstMoves[pos]
+		win, draw, loss := fm.Rates()
+		fmt.Fprintf(&sb, "  %s  %6d games (%.1f%%)  W/D/L %.1f%%/%.1f%%/%.1f%%  avg %.2f moves\n",
+			pos, fm.Games(), pct(fm.Games()), 100*win, 100*draw, 100*loss,
+			float64(fm.Plies)/float64(fm.Games()))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
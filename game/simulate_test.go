@@ -0,0 +1,138 @@
+package game
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestStrategiesPlayLegalMoves(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, name := range StrategyNames() {
+		strategy, err := NewStrategy(name)
+		if err != nil {
+			t.Fatalf("NewStrategy(%q) failed: %v", name, err)
+		}
+		for _, board := range reachableBoards() {
+			bb := bitboardOf(&board)
+			if bb.full() || hasWin(bb.x) || hasWin(bb.o) {
+				continue
+			}
+			player := PlayerX
+			if bb.count()%2 == 1 {
+				player = PlayerO
+			}
+			pos := strategy.ChooseMove(board, player, rng)
+			if !board.IsEmpty(pos) {
+				t.Fatalf("%s chose occupied square %s on\n%s", name, pos, board.String())
+			}
+		}
+	}
+
+	if _, err := NewStrategy("oracle"); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
+
+func TestGreedyStrategyTakesWinsAndBlocks(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	strategy, _ := NewStrategy("greedy")
+
+	// X wins at C1 rather than blocking O at C2
+	board, player, _ := ParseFEN("XX./OO./... x")
+	if pos := strategy.ChooseMove(board, player, rng); pos.String() != "C1" {
+		t.Errorf("Expected the win at C1, got %s", pos)
+	}
+
+	// O must block at C1
+	board, player, _ = ParseFEN("XX./O../... o")
+	if pos := strategy.ChooseMove(board, player, rng); pos.String() != "C1" {
+		t.Errorf("Expected the block at C1, got %s", pos)
+	}
+}
+
+func TestSimulatePerfectPlayNeverLoses(t *testing.T) {
+	result, err := Simulate(context.Background(), SimulationConfig{
+		Strategies: [2]string{"perfect", "perfect"},
+		Games:      500,
+		Workers:    4,
+		Seed:       7,
+		Alternate:  true,
+	})
+	if err != nil {
+		t.Fatalf("Simulate() failed: %v", err)
+	}
+	if result.Games != 500 || result.Draws != 500 {
+		t.Errorf("Perfect self-play should always draw, got %+v", result)
+	}
+	if result.AverageLength() != 9 {
+		t.Errorf("Drawn games last 9 moves, got %.2f", result.AverageLength())
+	}
+	for _, s := range result.Strategies {
+		if s.AsX.Games() != 250 || s.AsO.Games() != 250 {
+			t.Errorf("Alternating sides should split games evenly, got %+v", s)
+		}
+	}
+}
+
+func TestSimulateAgainstRandom(t *testing.T) {
+	cfg := SimulationConfig{
+		Strategies: [2]string{"perfect", "random"},
+		Games:      2000,
+		Workers:    3,
+		Seed:       42,
+		Alternate:  true,
+	}
+	result, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Simulate() failed: %v", err)
+	}
+
+	perfect, random := result.Strategies[0], result.Strategies[1]
+	if perfect.Total.Losses != 0 || perfect.Total.Wins != random.Total.Losses {
+		t.Errorf("Inconsistent strategy records: %+v vs %+v", perfect, random)
+	}
+	if perfect.WinRate.Low < 0.8 || perfect.WinRate.Low > perfect.WinRate.High {
+		t.Errorf("Unexpected win-rate interval %+v", perfect.WinRate)
+	}
+
+	firstMoves := 0
+	for _, fm := range result.FirstMoves {
+		firstMoves += fm.Games()
+	}
+	if firstMoves != result.Games || result.XWins+result.OWins+result.Draws != result.Games {
+		t.Errorf("First-move counts %d don't add up to %d games", firstMoves, result.Games)
+	}
+
+	again, _ := Simulate(context.Background(), cfg)
+	if again.XWins != result.XWins || again.TotalPlies != result.TotalPlies {
+		t.Error("Runs with the same seed and workers should be identical")
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	if _, err := Simulate(context.Background(), SimulationConfig{Strategies: [2]string{"random", "random"}}); err == nil {
+		t.Error("Expected an error for zero games")
+	}
+	if _, err := Simulate(context.Background(), SimulationConfig{Strategies: [2]string{"random", "nope"}, Games: 1}); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := Simulate(ctx, SimulationConfig{Strategies: [2]string{"random", "random"}, Games: 100})
+	if err == nil || result == nil || result.Games != 0 {
+		t.Errorf("Expected a cancelled, empty result, got %+v, %v", result, err)
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	interval := WilsonInterval(50, 100, 1.96)
+	if math.Abs(interval.Low-0.4038) > 1e-3 || math.Abs(interval.High-0.5962) > 1e-3 {
+		t.Errorf("Unexpected interval for 50/100: %+v", interval)
+	}
+	if interval := WilsonInterval(0, 10, 1.96); interval.Low != 0 || interval.High <= 0 {
+		t.Errorf("Zero successes should still have a positive upper bound, got %+v", interval)
+	}
+}
@@ -0,0 +1,254 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// Outcome is the result of a position under perfect play, from the point of
+// view of the player it is evaluated for
+type Outcome int
+
+const (
+	OutcomeLoss Outcome = -1
+	OutcomeDraw Outcome = 0
+	OutcomeWin  Outcome = 1
+)
+
+// String returns "win", "draw" or "loss"
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeWin:
+		return "win"
+	case OutcomeLoss:
+		return "loss"
+	}
+	return "draw"
+}
+
+// MarshalText encodes the outcome as "win", "draw" or "loss"
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// Evaluation is the solved value of a position. Plies counts the moves, by
+// both players, until the game ends when the winner wins as fast as possible
+// and the loser holds out as long as possible.
+type Evaluation struct {
+	Outcome Outcome `json:"outcome"`
+	Plies   int     `json:"plies"`
+}
+
+// String describes the evaluation, e.g. "win in 3 plies"
+func (e Evaluation) String() string {
+	if e.Outcome == OutcomeDraw {
+		return "draw"
+	}
+	return fmt.Sprintf("%s in %d plies", e.Outcome, e.Plies)
+}
+
+// Better reports whether e is strictly preferable to other for the player
+// they are evaluated for: faster wins and slower losses are better
+func (e Evaluation) Better(other Evaluation) bool {
+	return e.score() > other.score()
+}
+
+// score packs an evaluation into a single comparable number
+func (e Evaluation) score() int {
+	switch e.Outcome {
+	case OutcomeWin:
+		return solverWinScore - e.Plies
+	case OutcomeLoss:
+		return -solverWinScore + e.Plies
+	}
+	return 0
+}
+
+// evaluationFromScore reverses Evaluation.score
+func evaluationFromScore(score int) Evaluation {
+	switch {
+	case score > 0:
+		return Evaluation{Outcome: OutcomeWin, Plies: solverWinScore - score}
+	case score < 0:
+		return Evaluation{Outcome: OutcomeLoss, Plies: solverWinScore + score}
+	}
+	return Evaluation{Outcome: OutcomeDraw}
+}
+
+// solverWinScore is the score of a win on the spot; it is larger than the
+// longest possible game so that every win scores above zero
+const solverWinScore = 10
+
+// MoveEvaluation is the value of playing a move, for the player making it
+type MoveEvaluation struct {
+	Position   Position
+	Evaluation Evaluation
+}
+
+// Solver evaluates positions by exhaustive search over bitboards. Results are
+// cached, so a single Solver can be shared; it is safe for concurrent use.
+type Solver struct {
+	mutex sync.Mutex
+	cache []int8 // Scores indexed by solverIndex, solverUnknown until solved
+}
+
+// solverUnknown marks cache entries that have not been solved yet
+const solverUnknown = math.MinInt8
+
+// NewSolver creates a solver with an empty cache
+func NewSolver() *Solver {
+	cache := make([]int8, 1<<18)
+	for i := range cache {
+		cache[i] = solverUnknown
+	}
+	return &Solver{cache: cache}
+}
+
+// defaultSolver is shared by the package-level helpers and the engine
+var defaultSolver = NewSolver()
+
+// boardKey is a base-3 encoding of a board; it also determines the side to move
+type boardKey uint16
+
+// keyOf encodes a board as a boardKey
+func keyOf(b Board) boardKey {
+	var key boardKey
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			key *= 3
+			switch b[row][col] {
+			case PlayerX:
+				key++
+			case PlayerO:
+				key += 2
+			}
+		}
+	}
+	return key
+}
+
+// solverIndex packs both players' masks into a cache index. The side to move
+// follows from the piece counts, so it needs no bits of its own.
+func solverIndex(bb bitboard) int {
+	return int(bb.x)<<9 | int(bb.o)
+}
+
+// Evaluate returns the value of the position for the player to move
+func (s *Solver) Evaluate(board Board, toMove Player) Evaluation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return evaluationFromScore(s.negamax(bitboardOf(&board), toMove))
+}
+
+// EvaluateMoves returns the value of every legal move for the player to move,
+// in row-major order
+func (s *Solver) EvaluateMoves(board Board, toMove Player) []MoveEvaluation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	bb := bitboardOf(&board)
+	if bb.terminal(toMove) {
+		return nil
+	}
+
+	opponent := toMove.Opponent()
+	var moves []MoveEvaluation
+	for empty := bb.empty(); empty != 0; empty &= empty - 1 {
+		index := bits.TrailingZeros16(empty)
+		moves = append(moves, MoveEvaluation{
+			Position:   cellPosition(index),
+			Evaluation: evaluationFromScore(backUp(s.negamax(bb.play(index, toMove), opponent))),
+		})
+	}
+	return moves
+}
+
+// BestMoves returns the moves with the best evaluation for the player to move
+func (s *Solver) BestMoves(board Board, toMove Player) []Position {
+	moves := s.EvaluateMoves(board, toMove)
+	var best []Position
+	var bestEval Evaluation
+	for i, move := range moves {
+		switch {
+		case i == 0 || move.Evaluation.Better(bestEval):
+			best = []Position{move.Position}
+			bestEval = move.Evaluation
+		case !bestEval.Better(move.Evaluation):
+			best = append(best, move.Position)
+		}
+	}
+	return best
+}
+
+// terminal reports whether the game is already over in this position
+func (bb bitboard) terminal(toMove Player) bool {
+	return hasWin(bb.of(toMove.Opponent())) || bb.full()
+}
+
+// negamax returns the score of the position for the player to move. The
+// caller must hold the mutex.
+func (s *Solver) negamax(bb bitboard, toMove Player) int {
+	index := solverIndex(bb)
+	if score := s.cache[index]; score != solverUnknown {
+		return int(score)
+	}
+
+	var score int
+	switch {
+	case hasWin(bb.of(toMove.Opponent())):
+		score = -solverWinScore
+	case bb.full():
+		score = 0
+	default:
+		score = -solverWinScore - 1
+		opponent := toMove.Opponent()
+		for empty := bb.empty(); empty != 0; empty &= empty - 1 {
+			next := bb.play(bits.TrailingZeros16(empty), toMove)
+			if child := backUp(s.negamax(next, opponent)); child > score {
+				score = child
+			}
+		}
+	}
+
+	s.cache[index] = int8(score)
+	return score
+}
+
+// backUp converts a child's score into its parent's perspective, one ply
+// further from the end of the game
+func backUp(child int) int {
+	score := -child
+	if score > 0 {
+		score--
+	} else if score < 0 {
+		score++
+	}
+	return score
+}
+
+// Opponent returns the other player
+func (p Player) Opponent() Player {
+	if p == PlayerX {
+		return PlayerO
+	}
+	return PlayerX
+}
+
+// Evaluate returns the value of the position for the player to move using
+// the shared solver
+func Evaluate(board Board, toMove Player) Evaluation {
+	return defaultSolver.Evaluate(board, toMove)
+}
+
+// EvaluateMoves returns the value of every legal move using the shared solver
+func EvaluateMoves(board Board, toMove Player) []MoveEvaluation {
+	return defaultSolver.EvaluateMoves(board, toMove)
+}
+
+// BestMoves returns the best moves for the player to move using the shared
+// solver
+func BestMoves(board Board, toMove Player) []Position {
+	return defaultSolver.BestMoves(board, toMove)
+}
@@ -0,0 +1,63 @@
+package game
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	// Perfect play from the empty board is a draw
+	if eval := Evaluate(NewBoard(), PlayerX); eval.Outcome != OutcomeDraw {
+		t.Errorf("Empty board should be a draw, got %s", eval)
+	}
+
+	testCases := []struct {
+		fen      string
+		expected Evaluation
+		best     []string
+	}{
+		// X completes row 1 immediately
+		{"XX./OO./... x", Evaluation{OutcomeWin, 1}, []string{"C1"}},
+		// X already threatens row 1 and column C, so O loses whatever it plays
+		{"X.X/.O./O.X o", Evaluation{OutcomeLoss, 2}, []string{"B1", "A2", "C2", "B3"}},
+		// Against opposite corners O holds the draw, but only by playing an edge
+		{"X../.O./..X o", Evaluation{OutcomeDraw, 0}, []string{"B1", "A2", "C2", "B3"}},
+		// Game already won by X
+		{"XXX/OO./... o", Evaluation{OutcomeLoss, 0}, nil},
+	}
+
+	for _, tc := range testCases {
+		board, toMove, err := ParseFEN(tc.fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) failed: %v", tc.fen, err)
+		}
+		if eval := Evaluate(board, toMove); eval != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.fen, tc.expected, eval)
+		}
+		if tc.best == nil {
+			continue
+		}
+		best := BestMoves(board, toMove)
+		if len(best) != len(tc.best) {
+			t.Errorf("%s: expected best moves %v, got %v", tc.fen, tc.best, best)
+			continue
+		}
+		for i := range best {
+			if best[i].String() != tc.best[i] {
+				t.Errorf("%s: expected best moves %v, got %v", tc.fen, tc.best, best)
+			}
+		}
+	}
+}
+
+func TestEvaluationOrdering(t *testing.T) {
+	fastWin := Evaluation{OutcomeWin, 1}
+	slowWin := Evaluation{OutcomeWin, 5}
+	draw := Evaluation{OutcomeDraw, 0}
+	slowLoss := Evaluation{OutcomeLoss, 6}
+	fastLoss := Evaluation{OutcomeLoss, 2}
+
+	ordered := []Evaluation{fastWin, slowWin, draw, slowLoss, fastLoss}
+	for i := 0; i < len(ordered)-1; i++ {
+		if !ordered[i].Better(ordered[i+1]) {
+			t.Errorf("Expected %s to be better than %s", ordered[i], ordered[i+1])
+		}
+	}
+}
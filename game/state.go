@@ -0,0 +1,100 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// stateVersion is the format version written by SaveState
+const stateVersion = 1
+
+// engineState is the on-disk form of every game in an engine
+type engineState struct {
+	Version int          `json:"version"`
+	SavedAt time.Time    `json:"saved_at"`
+	Games   []*GameState `json:"games"`
+}
+
+// SaveState writes every game to path as JSON. The file is replaced
+// atomically, so a crash mid-write leaves the previous state intact.
+func (e *Engine) SaveState(path string) error {
+	e.mutex.RLock()
+	state := engineState{
+		Version: stateVersion,
+		SavedAt: time.Now(),
+		Games:   make([]*GameState, 0, len(e.games)),
+	}
+	for _, game := range e.games {
+		state.Games = append(state.Games, game.Clone())
+	}
+	e.mutex.RUnlock()
+
+	sort.Slice(state.Games, func(i, j int) bool {
+		return state.Games[i].GameID < state.Games[j].GameID
+	})
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state failed: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing state failed: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing state failed: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing state failed: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing state failed: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing state failed: %v", err)
+	}
+	return nil
+}
+
+// LoadState restores the games saved by SaveState, replacing any games
+// with the same IDs, and returns how many it loaded. A missing file loads
+// nothing. No events are published for restored games.
+func (e *Engine) LoadState(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading state failed: %v", err)
+	}
+
+	var state engineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return 0, fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+	if state.Version != stateVersion {
+		return 0, fmt.Errorf("unsupported state file version %d", state.Version)
+	}
+	for _, game := range state.Games {
+		if game == nil || game.GameID == "" {
+			return 0, fmt.Errorf("invalid state file %s: game without an ID", path)
+		}
+		if game.Players == nil {
+			game.Players = make(map[Player]string)
+		}
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, game := range state.Games {
+		e.games[game.GameID] = game
+	}
+	return len(state.Games), nil
+}
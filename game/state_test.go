@@ -0,0 +1,108 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndLoadState(t *testing.T) {
+	engine := NewEngine()
+	engine.CreateGameWithOptions("named", GameOptions{Players: map[Player]string{PlayerX: "alice", PlayerO: "bob"}})
+	playMoves(t, engine, "named", "B2", "A1")
+	engine.CreateGameWithOptions("setup", GameOptions{FEN: "X.O/.X./..O x"})
+	engine.CreatePuzzleGame("puzzle", easiestPuzzle(t, PuzzleWinInN))
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := engine.SaveState(path); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	restored := NewEngine()
+	events := recordEvents(restored)
+	n, err := restored.LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 games loaded, got %d", n)
+	}
+	if len(*events) != 0 {
+		t.Errorf("Expected no events when loading, got %v", eventTypes(*events))
+	}
+
+	for _, id := range []string{"named", "setup", "puzzle"} {
+		want, _ := engine.Snapshot(id)
+		got, err := restored.Snapshot(id)
+		if err != nil {
+			t.Fatalf("Game %s was not restored: %v", id, err)
+		}
+		if got.FEN() != want.FEN() || got.StartFEN != want.StartFEN || got.Variant != want.Variant ||
+			len(got.Moves) != len(want.Moves) || !got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("Game %s restored as %+v, want %+v", id, got, want)
+		}
+	}
+	named, _ := restored.Snapshot("named")
+	if named.Players[PlayerX] != "alice" || named.Moves[1].Position.String() != "A1" {
+		t.Errorf("Unexpected restored players or moves: %v %v", named.Players, named.Moves)
+	}
+	if puzzle, _ := restored.Snapshot("puzzle"); puzzle.Puzzle == nil || len(puzzle.Puzzle.Solutions) == 0 {
+		t.Error("Expected the puzzle to be restored")
+	}
+
+	// Restored games carry on where they left off
+	if _, err := restored.MakeMove("named", Position{Row: 0, Col: 2}, PlayerX); err != nil {
+		t.Errorf("Playing a restored game failed: %v", err)
+	}
+}
+
+func TestLoadStateMissingFile(t *testing.T) {
+	n, err := NewEngine().LoadState(filepath.Join(t.TempDir(), "absent.json"))
+	if err != nil || n != 0 {
+		t.Errorf("Expected nothing loaded from a missing file, got %d, %v", n, err)
+	}
+}
+
+func TestLoadStateRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"corrupt", "{not json", "invalid state file"},
+		{"future version", `{"version": 99, "games": []}`, "unsupported state file version"},
+		{"missing ID", `{"version": 1, "games": [{"Status": "ongoing"}]}`, "game without an ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			os.WriteFile(path, []byte(tt.content), 0o644)
+
+			engine := NewEngine()
+			_, err := engine.LoadState(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+			if len(engine.ListGames()) != 0 {
+				t.Error("Expected no games loaded from a bad file")
+			}
+		})
+	}
+}
+
+func TestBoardJSONIsGrid(t *testing.T) {
+	board, _, _ := ParseFEN("X.O/.X./..O x")
+	data, err := json.Marshal(board)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `[["X","","O"],["","X",""],["","","O"]]`; string(data) != want {
+		t.Errorf("Board encoded as %s, want %s", data, want)
+	}
+	var decoded Board
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != board {
+		t.Errorf("Round trip gave %v (%v)", decoded, err)
+	}
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+// Strategy chooses moves for an automated player. Implementations may keep
+// internal state and need not be safe for concurrent use; create one per
+// goroutine with NewStrategy.
+type Strategy interface {
+	Name() string
+	// ChooseMove returns a legal move for player on an undecided board
+	ChooseMove(board Board, player Player, rng *rand.Rand) Position
+}
+
+// strategyFactories builds each named strategy
+var strategyFactories = map[string]func() Strategy{
+	"random":    func() Strategy { return randomStrategy{} },
+	"greedy":    func() Strategy { return greedyStrategy{} },
+	"heuristic": func() Strategy { return heuristicStrategy{} },
+	"perfect":   func() Strategy { return &perfectStrategy{solver: NewSolver()} },
+}
+
+// StrategyNames returns the names accepted by NewStrategy, sorted
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategyFactories))
+	for name := range strategyFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewStrategy creates a strategy by name:
+//
+//   - random: any legal move
+//   - greedy: wins or blocks immediate threats, otherwise random
+//   - heuristic: the classic rule list (win, block, fork, block fork,
+//     centre, opposite corner, corner, edge)
+//   - perfect: a random choice among the solver's best moves
+func NewStrategy(name string) (Strategy, error) {
+	factory, ok := strategyFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q (available: %v)", name, StrategyNames())
+	}
+	return factory(), nil
+}
+
+// pick returns a uniformly random element of a non-empty slice
+func pick(positions []Position, rng *rand.Rand) Position {
+	return positions[rng.IntN(len(positions))]
+}
+
+type randomStrategy struct{}
+
+func (randomStrategy) Name() string { return "random" }
+
+func (randomStrategy) ChooseMove(board Board, player Player, rng *rand.Rand) Position {
+	return pick(positions(bitboardOf(&board).empty()), rng)
+}
+
+type greedyStrategy struct{}
+
+func (greedyStrategy) Name() string { return "greedy" }
+
+func (greedyStrategy) ChooseMove(board Board, player Player, rng *rand.Rand) Position {
+	if wins := board.WinningSquares(player); len(wins) > 0 {
+		return pick(wins, rng)
+	}
+	if blocks := board.WinningSquares(player.Opponent()); len(blocks) > 0 {
+		return pick(blocks, rng)
+	}
+	return randomStrategy{}.ChooseMove(board, player, rng)
+}
+
+type heuristicStrategy struct{}
+
+func (heuristicStrategy) Name() string { return "heuristic" }
+
+func (heuristicStrategy) ChooseMove(board Board, player Player, rng *rand.Rand) Position {
+	opponent := player.Opponent()
+
+	if wins := board.WinningSquares(player); len(wins) > 0 {
+		return pick(wins, rng)
+	}
+	if blocks := board.WinningSquares(opponent); len(blocks) > 0 {
+		return pick(blocks, rng)
+	}
+	if forks := board.ForkSquares(player); len(forks) > 0 {
+		return pick(forks, rng)
+	}
+	if forks := board.ForkSquares(opponent); len(forks) > 0 {
+		return pick(forks, rng)
+	}
+
+	center := Position{Row: 1, Col: 1}
+	if board.IsEmpty(center) {
+		return center
+	}
+
+	var opposite, corners, edges []Position
+	for _, pos := range board.EmptyPositions() {
+		switch {
+		case pos.Row != 1 && pos.Col != 1:
+			corners = append(corners, pos)
+			if board.Get(Position{Row: 2 - pos.Row, Col: 2 - pos.Col}) == opponent {
+				opposite = append(opposite, pos)
+			}
+		default:
+			edges = append(edges, pos)
+		}
+	}
+	switch {
+	case len(opposite) > 0:
+		return pick(opposite, rng)
+	case len(corners) > 0:
+		return pick(corners, rng)
+	}
+	return pick(edges, rng)
+}
+
+// perfectStrategy has its own solver so concurrent simulations don't
+// contend on the shared one
+type perfectStrategy struct {
+	solver *Solver
+}
+
+func (*perfectStrategy) Name() string { return "perfect" }
+
+func (s *perfectStrategy) ChooseMove(board Board, player Player, rng *rand.Rand) Position {
+	return pick(s.solver.BestMoves(board, player), rng)
+}
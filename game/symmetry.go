@@ -0,0 +1,123 @@
+package game
+
+import "fmt"
+
+// Symmetry is one of the eight rotations and reflections of the board (the
+// dihedral group of the square)
+type Symmetry int
+
+const (
+	Identity         Symmetry = iota
+	Rotate90                  // Clockwise quarter turn
+	Rotate180                 // Half turn
+	Rotate270                 // Counter-clockwise quarter turn
+	FlipHorizontal            // Mirror left-right (column A <-> column C)
+	FlipVertical              // Mirror top-bottom (row 1 <-> row 3)
+	FlipDiagonal              // Mirror across the A1-C3 diagonal
+	FlipAntiDiagonal          // Mirror across the C1-A3 diagonal
+)
+
+// Symmetries lists all eight symmetries, identity first
+var Symmetries = []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipHorizontal, FlipVertical, FlipDiagonal, FlipAntiDiagonal}
+
+var symmetryNames = map[Symmetry]string{
+	Identity:         "identity",
+	Rotate90:         "rotate-90",
+	Rotate180:        "rotate-180",
+	Rotate270:        "rotate-270",
+	FlipHorizontal:   "flip-horizontal",
+	FlipVertical:     "flip-vertical",
+	FlipDiagonal:     "flip-diagonal",
+	FlipAntiDiagonal: "flip-anti-diagonal",
+}
+
+// String returns the symmetry's name, e.g. "rotate-90"
+func (s Symmetry) String() string {
+	if name, ok := symmetryNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Symmetry(%d)", int(s))
+}
+
+// MarshalText encodes the symmetry by name
+func (s Symmetry) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Apply maps a position to where the symmetry moves it
+func (s Symmetry) Apply(p Position) Position {
+	r, c := p.Row, p.Col
+	switch s {
+	case Rotate90:
+		return Position{Row: c, Col: 2 - r}
+	case Rotate180:
+		return Position{Row: 2 - r, Col: 2 - c}
+	case Rotate270:
+		return Position{Row: 2 - c, Col: r}
+	case FlipHorizontal:
+		return Position{Row: r, Col: 2 - c}
+	case FlipVertical:
+		return Position{Row: 2 - r, Col: c}
+	case FlipDiagonal:
+		return Position{Row: c, Col: r}
+	case FlipAntiDiagonal:
+		return Position{Row: 2 - c, Col: 2 - r}
+	}
+	return p
+}
+
+// Inverse returns the symmetry that undoes s
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	}
+	// Half turns and reflections are their own inverses
+	return s
+}
+
+// Transform returns the board with the symmetry applied
+func (b *Board) Transform(s Symmetry) Board {
+	var out Board
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			pos := Position{Row: row, Col: col}
+			out.Set(s.Apply(pos), b.Get(pos))
+		}
+	}
+	return out
+}
+
+// PositionHash identifies a board up to symmetry: boards that are rotations
+// or reflections of each other hash to the same value. It is the base-3
+// encoding (empty 0, X 1, O 2, A1 most significant) of the canonical board,
+// so it is stable across runs and versions.
+type PositionHash uint16
+
+// String formats the hash as five decimal digits
+func (h PositionHash) String() string {
+	return fmt.Sprintf("%05d", uint16(h))
+}
+
+// Canonical returns the canonical form of the board, the symmetric variant
+// with the smallest encoding, and the symmetry that maps the board onto it.
+// Ties between symmetries resolve to the first in Symmetries.
+func (b *Board) Canonical() (Board, Symmetry) {
+	best, bestSym := *b, Identity
+	bestKey := keyOf(best)
+	for _, s := range Symmetries[1:] {
+		candidate := b.Transform(s)
+		if key := keyOf(candidate); key < bestKey {
+			best, bestSym, bestKey = candidate, s, key
+		}
+	}
+	return best, bestSym
+}
+
+// Hash returns the symmetry-aware hash of the board
+func (b *Board) Hash() PositionHash {
+	canonical, _ := b.Canonical()
+	return PositionHash(keyOf(canonical))
+}
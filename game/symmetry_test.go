@@ -0,0 +1,76 @@
+package game
+
+import "testing"
+
+func TestSymmetryTransforms(t *testing.T) {
+	a1, _ := ParsePosition("A1")
+	expected := map[Symmetry]string{
+		Identity:         "A1",
+		Rotate90:         "C1",
+		Rotate180:        "C3",
+		Rotate270:        "A3",
+		FlipHorizontal:   "C1",
+		FlipVertical:     "A3",
+		FlipDiagonal:     "A1",
+		FlipAntiDiagonal: "C3",
+	}
+	for s, want := range expected {
+		if got := s.Apply(a1).String(); got != want {
+			t.Errorf("%s maps A1 to %s, expected %s", s, got, want)
+		}
+	}
+
+	board, _, _ := ParseFEN("XO./.X./..O x")
+	for _, s := range Symmetries {
+		transformed := board.Transform(s)
+		if back := transformed.Transform(s.Inverse()); back != board {
+			t.Errorf("%s followed by its inverse should restore the board", s)
+		}
+		if transformed.Hash() != board.Hash() {
+			t.Errorf("%s changed the position hash", s)
+		}
+	}
+
+	canonical, symmetry := board.Canonical()
+	if board.Transform(symmetry) != canonical {
+		t.Errorf("Applying %s should produce the canonical board", symmetry)
+	}
+}
+
+func TestCanonicalPositionCount(t *testing.T) {
+	// Tic-tac-toe has 5478 legal positions but only 765 up to symmetry
+	raw := make(map[boardKey]bool)
+	canonical := make(map[PositionHash]bool)
+
+	var visit func(board Board, toMove Player)
+	visit = func(board Board, toMove Player) {
+		if raw[keyOf(board)] {
+			return
+		}
+		raw[keyOf(board)] = true
+		canonical[board.Hash()] = true
+
+		if len(board.CompletedLines(toMove.Opponent())) > 0 || board.IsFull() {
+			return
+		}
+		for _, pos := range board.EmptyPositions() {
+			next := board
+			next.Set(pos, toMove)
+			visit(next, toMove.Opponent())
+		}
+	}
+	visit(NewBoard(), PlayerX)
+
+	if len(raw) != 5478 {
+		t.Errorf("Expected 5478 positions, got %d", len(raw))
+	}
+	if len(canonical) != 765 {
+		t.Errorf("Expected 765 positions up to symmetry, got %d", len(canonical))
+	}
+
+	corner, _, _ := ParseFEN("X../.../... o")
+	edge, _, _ := ParseFEN(".X./.../... o")
+	if corner.Hash() == edge.Hash() {
+		t.Error("Corner and edge openings should hash differently")
+	}
+}
@@ -1,6 +1,9 @@
 package game
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Player represents a player in the game
 type Player string
@@ -25,6 +28,24 @@ func (p Position) String() string {
 	return fmt.Sprintf("%c%d", 'A'+p.Col, p.Row+1)
 }
 
+// MarshalText encodes the position in A1-C3 format
+func (p Position) MarshalText() ([]byte, error) {
+	if p.Row < 0 || p.Row > 2 || p.Col < 0 || p.Col > 2 {
+		return nil, fmt.Errorf("position %d,%d is out of bounds", p.Row, p.Col)
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a position in A1-C3 format
+func (p *Position) UnmarshalText(text []byte) error {
+	pos, err := ParsePosition(string(text))
+	if err != nil {
+		return err
+	}
+	*p = pos
+	return nil
+}
+
 // ParsePosition converts A1-C3 notation to Position
 func ParsePosition(pos string) (Position, error) {
 	if len(pos) != 2 {
@@ -50,6 +71,14 @@ const (
 	StatusDraw    GameStatus = "draw"
 )
 
+// Variant identifies the rule set or setup a game was started with
+type Variant string
+
+const (
+	VariantStandard Variant = "standard"
+	VariantSetup    Variant = "setup" // Started from an arbitrary position
+)
+
 // Board represents the 3x3 tic-tac-toe board
 type Board [3][3]Player
 
@@ -105,18 +134,38 @@ func (b *Board) String() string {
 	return result
 }
 
+// Move records a single mark placed during a game
+type Move struct {
+	Player   Player
+	Position Position
+}
+
 // GameState represents the complete state of a game
 type GameState struct {
-	Board         Board
-	CurrentPlayer Player
-	Status        GameStatus
-	Winner        Player
-	MoveCount     int
-	GameID        string
+	Board          Board
+	CurrentPlayer  Player
+	Status         GameStatus
+	Winner         Player
+	MoveCount      int
+	Moves          []Move  // Moves played so far, in order
+	StartFEN       string  // Starting position for setup games, empty for the standard start
+	Puzzle         *Puzzle // Set for puzzle games
+	PuzzleAttempts int
+	PuzzleSolved   bool
+	HintsUsed      int // Hint points spent, see Engine.GetHint
+	HintBudget     int
+	GameID         string
+	Variant        Variant
+	Players        map[Player]string // Optional participant names keyed by mark
+	Owner          string            // Authenticated principal that created the game, if any
+	Seats          map[Player]string // Authenticated principals bound to each mark, see Engine.ClaimSeat
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // NewGame creates a new game state
 func NewGame(gameID string) *GameState {
+	now := time.Now()
 	return &GameState{
 		Board:         NewBoard(),
 		CurrentPlayer: PlayerX, // X always goes first
@@ -124,6 +173,11 @@ func NewGame(gameID string) *GameState {
 		Winner:        Empty,
 		MoveCount:     0,
 		GameID:        gameID,
+		Variant:       VariantStandard,
+		Players:       make(map[Player]string),
+		HintBudget:    DefaultHintBudget,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 }
 
@@ -2,7 +2,10 @@ module mcp-tic-tac-toe
 
 go 1.24.5
 
-require github.com/mark3labs/mcp-go v0.33.0
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/mark3labs/mcp-go v0.33.0
+)
 
 require (
 	github.com/google/uuid v1.6.0 // indirect
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxAuditText caps each argument and the result or error text kept per
+// audit record, so a large record, board or review does not bloat the log
+const maxAuditText = 4096
+
+// AuditRecord is a line in the audit log: one tool call and how it ended
+type AuditRecord struct {
+	Time       time.Time      `json:"time"` // When the call started
+	Session    string         `json:"session,omitempty"`
+	Transport  string         `json:"transport,omitempty"`
+	Principal  string         `json:"principal,omitempty"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	GameID     string         `json:"game_id,omitempty"`
+	Result     string         `json:"result,omitempty"` // Result text of a successful call
+	Error      string         `json:"error,omitempty"`  // Error text of a failed or refused call
+	DurationMS float64        `json:"duration_ms"`
+}
+
+// auditLog writes audit records as JSON lines
+type auditLog struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+// EnableAudit writes a record of every tool call to w as a JSON line. Pass
+// a RotatingFile to keep the log from growing without bound.
+func (s *TicTacToeServer) EnableAudit(w io.Writer) {
+	s.audit = &auditLog{w: w}
+}
+
+func (a *auditLog) write(record AuditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		slog.Error("Encoding audit record failed", "tool", record.Tool, "error", err)
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		slog.Error("Writing audit record failed", "tool", record.Tool, "error", err)
+	}
+}
+
+// auditToolCalls is tool handler middleware that logs every tool call and,
+// if enabled, records it in the audit log. It runs outside the middleware
+// that refuses calls, so refusals are recorded too. When the audit log or the
+// open-game quota needs it, it chooses the ID of the game a creating tool
+// will open, and passes it on to limitToolCalls in the context.
+func (s *TicTacToeServer) auditToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		gameID, _ := request.GetArguments()["game_id"].(string)
+		if creatingTools[request.Params.Name] && (s.audit != nil || s.quotas != nil) {
+			gameID = createdGameID(&request)
+			ctx = withCreatedGameID(ctx, gameID)
+		}
+
+		start := time.Now()
+		result, err := next(ctx, request)
+		record := AuditRecord{
+			Time:       start,
+			Tool:       request.Params.Name,
+			Arguments:  truncateArguments(request.GetArguments()),
+			GameID:     gameID,
+			DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			record.Session = session.SessionID()
+			record.Transport = s.sessionTransport(ctx, session)
+		}
+		if principal := PrincipalFromContext(ctx); principal != nil {
+			record.Principal = principal.Name
+		}
+		switch {
+		case err != nil:
+			record.Error = truncateAudit(err.Error())
+		case result != nil && result.IsError:
+			record.Error = truncateAudit(resultText(result))
+		case result != nil:
+			record.Result = truncateAudit(resultText(result))
+		}
+
+		slog.Debug("Tool call", "tool", record.Tool, "session", record.Session, "game_id", record.GameID,
+			"outcome", outcome(record.Error == ""), "duration_ms", record.DurationMS)
+		if s.audit != nil {
+			s.audit.write(record)
+		}
+		return result, err
+	}
+}
+
+// resultText joins the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// truncateArguments returns the arguments with long values cut to
+// maxAuditText. Other values are kept as they are; a long value that is not
+// a string is replaced by its truncated JSON.
+func truncateArguments(args map[string]any) map[string]any {
+	var truncated map[string]any
+	for name, value := range args {
+		text, isString := value.(string)
+		if !isString {
+			encoded, _ := json.Marshal(value)
+			text = string(encoded)
+		}
+		if len(text) <= maxAuditText {
+			continue
+		}
+		if truncated == nil {
+			truncated = make(map[string]any, len(args))
+			for name, value := range args {
+				truncated[name] = value
+			}
+		}
+		truncated[name] = truncateAudit(text)
+	}
+	if truncated == nil {
+		return args
+	}
+	return truncated
+}
+
+func truncateAudit(text string) string {
+	if len(text) <= maxAuditText {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxAuditText], "") + "…"
+}
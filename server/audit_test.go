@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// auditRecords decodes the JSON lines written to an audit log
+func auditRecords(t *testing.T, log *bytes.Buffer) []AuditRecord {
+	t.Helper()
+	var records []AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(log.String()), "\n") {
+		var record AuditRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Bad audit line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAuditToolCalls(t *testing.T) {
+	server := NewTicTacToeServer()
+	var log bytes.Buffer
+	server.EnableAudit(&log)
+	server.EnableLimits(LimitOptions{MaxOpenGames: 1})
+
+	ctx := context.WithValue(sessionContext(t, server, "audited"), principalKey{}, &Principal{Name: "alice", Method: AuthToken})
+	callThroughServer(t, server, ctx, "new_game", map[string]any{"player_x": "Ann"})
+	callThroughServer(t, server, ctx, "new_game", map[string]any{})
+	callThroughServer(t, server, context.Background(), "get_board", map[string]any{"game_id": "missing"})
+
+	records := auditRecords(t, &log)
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 audit records, got %d", len(records))
+	}
+
+	created := records[0]
+	if created.Tool != "new_game" || created.Session != "audited" || created.Principal != "alice" || created.Transport != "other" {
+		t.Errorf("Unexpected caller in %+v", created)
+	}
+	if !strings.HasPrefix(created.GameID, "game-") || created.Arguments["game_id"] != created.GameID || created.Arguments["player_x"] != "Ann" {
+		t.Errorf("Expected the generated game ID in the record, got %+v", created)
+	}
+	if _, err := server.engine.GetGame(created.GameID); err != nil {
+		t.Errorf("Expected the recorded game ID to be the game created: %v", err)
+	}
+	if created.Error != "" || !strings.Contains(created.Result, created.GameID) || created.Time.IsZero() || created.DurationMS < 0 {
+		t.Errorf("Unexpected outcome in %+v", created)
+	}
+
+	// Calls refused by other middleware are recorded too
+	if refused := records[1]; !strings.Contains(refused.Error, "Open game quota reached") || refused.Result != "" {
+		t.Errorf("Expected the refused call's error, got %+v", refused)
+	}
+	if failed := records[2]; failed.GameID != "missing" || failed.Session != "" || !strings.Contains(failed.Error, "not found") {
+		t.Errorf("Expected the failed call's error, got %+v", failed)
+	}
+}
+
+func TestAuditImportWithoutGameTag(t *testing.T) {
+	server := NewTicTacToeServer()
+	var log bytes.Buffer
+	server.EnableAudit(&log)
+
+	callThroughServer(t, server, context.Background(), "import_game", map[string]any{"record": "1. B2 A1 *"})
+	records := auditRecords(t, &log)
+	if len(records) != 1 || records[0].GameID == "" {
+		t.Fatalf("Expected the chosen game ID in the record, got %+v", records)
+	}
+	if _, err := server.engine.Snapshot(records[0].GameID); err != nil {
+		t.Errorf("Expected the recorded game ID to be the game imported: %v", err)
+	}
+}
+
+func TestTruncateAudit(t *testing.T) {
+	if got := truncateAudit("short"); got != "short" {
+		t.Errorf("Expected short text to be kept, got %q", got)
+	}
+	long := strings.Repeat("é", maxAuditText)
+	got := truncateAudit(long)
+	if len(got) > maxAuditText+len("…") || !strings.HasSuffix(got, "…") || !strings.HasPrefix(long, strings.TrimSuffix(got, "…")) {
+		t.Errorf("Unexpected truncation to %d bytes", len(got))
+	}
+}
+
+func TestAuditTruncatesArguments(t *testing.T) {
+	server := NewTicTacToeServer()
+	var log bytes.Buffer
+	server.EnableAudit(&log)
+
+	record := "[Event \"" + strings.Repeat("x", 2*maxAuditText) + "\"]\n\n1. B2 *"
+	nested := []any{strings.Repeat("y", 2*maxAuditText)}
+	callThroughServer(t, server, context.Background(), "import_game", map[string]any{"record": record, "game_id": "long", "extra": nested})
+
+	records := auditRecords(t, &log)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 audit record, got %d", len(records))
+	}
+	logged := records[0].Arguments
+	if text, _ := logged["record"].(string); len(text) > maxAuditText+len("…") || !strings.HasPrefix(record, strings.TrimSuffix(text, "…")) {
+		t.Errorf("Expected the record argument truncated, got %d bytes", len(text))
+	}
+	if text, _ := logged["extra"].(string); len(text) > maxAuditText+len("…") || !strings.HasPrefix(text, `["yyy`) {
+		t.Errorf("Expected the long nested argument as truncated JSON, got %.20q", logged["extra"])
+	}
+	if logged["game_id"] != "long" {
+		t.Errorf("Expected short arguments to be kept, got %v", logged["game_id"])
+	}
+}
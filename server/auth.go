@@ -0,0 +1,348 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Authentication methods reported in Principal.Method
+const (
+	AuthToken = "token"
+	AuthJWT   = "jwt"
+	AuthMTLS  = "mtls"
+)
+
+// Principal is an authenticated caller of the network transports
+type Principal struct {
+	Name   string // Token name, JWT subject or certificate common name
+	Method string // How the caller authenticated: token, jwt or mtls
+}
+
+// Authenticator identifies the caller of an HTTP request
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// principalKey is the context key for the request's principal
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal that authenticated the request
+// a handler is serving, or nil for unauthenticated transports such as stdio
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// EnableAuth requires every request to the HTTP listeners to authenticate.
+// Stdio is local and stays open.
+func (s *TicTacToeServer) EnableAuth(auth Authenticator) {
+	s.auth = auth
+}
+
+// authenticate wraps an HTTP handler so that only authenticated requests
+// reach it, with their principal in the request context
+func (s *TicTacToeServer) authenticate(next http.Handler) http.Handler {
+	if s.auth == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := s.auth.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="tictactoe"`)
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+// sessionPrincipals binds MCP sessions to the principal that opened them
+type sessionPrincipals struct {
+	principals sync.Map // Session ID -> *Principal
+}
+
+// addAuthHooks remembers which principal opened each MCP session
+func (s *TicTacToeServer) addAuthHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		if principal := PrincipalFromContext(ctx); principal != nil {
+			s.sessions.principals.Store(session.SessionID(), principal)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.sessions.principals.Delete(session.SessionID())
+	})
+}
+
+// checkSessionPrincipal is tool handler middleware that stops one principal
+// from calling tools in a session another principal opened, e.g. by posting
+// to someone else's SSE session ID
+func (s *TicTacToeServer) checkSessionPrincipal(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		principal := PrincipalFromContext(ctx)
+		if session := server.ClientSessionFromContext(ctx); principal != nil && session != nil {
+			if owner, ok := s.sessions.principals.Load(session.SessionID()); ok && owner.(*Principal).Name != principal.Name {
+				return mcp.NewToolResultError("Session belongs to another client"), nil
+			}
+		}
+		return next(ctx, request)
+	}
+}
+
+// ownerFor returns the name to record as the owner of a game the caller
+// creates, empty if the caller is unauthenticated
+func ownerFor(ctx context.Context) string {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return principal.Name
+	}
+	return ""
+}
+
+// checkOwner fails if the caller is authenticated and did not create the
+// game. Missing games pass, so the engine reports them.
+func (s *TicTacToeServer) checkOwner(ctx context.Context, gameID string) error {
+	g, err := s.engine.Snapshot(gameID)
+	if err != nil {
+		return nil
+	}
+	return g.CheckOwner(ownerFor(ctx))
+}
+
+// bearerToken returns the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("missing bearer token")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// TokenAuthenticator accepts static bearer tokens
+type TokenAuthenticator struct {
+	names map[[sha256.Size]byte]string // Token hash -> principal name
+}
+
+// LoadTokenFile reads bearer tokens from a file. Each line holds a principal
+// name and its token separated by whitespace; blank lines and lines starting
+// with # are ignored.
+func LoadTokenFile(path string) (*TokenAuthenticator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading token file failed: %v", err)
+	}
+	defer file.Close()
+
+	auth := &TokenAuthenticator{names: make(map[[sha256.Size]byte]string)}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("token file %s line %d: expected a name and a token", path, line)
+		}
+		hash := sha256.Sum256([]byte(fields[1]))
+		if _, exists := auth.names[hash]; exists {
+			return nil, fmt.Errorf("token file %s line %d: duplicate token", path, line)
+		}
+		auth.names[hash] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading token file failed: %v", err)
+	}
+	if len(auth.names) == 0 {
+		return nil, fmt.Errorf("token file %s has no tokens", path)
+	}
+	return auth, nil
+}
+
+// Authenticate looks up the request's bearer token
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	// Tokens are compared by hash, so lookups take the same time whatever
+	// prefix of a valid token is guessed
+	name, ok := a.names[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &Principal{Name: name, Method: AuthToken}, nil
+}
+
+// JWTClaims are the registered JWT claims checked by JWTAuthenticator
+type JWTClaims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss,omitempty"`
+	Audience  JWTAudience `json:"aud,omitempty"`
+	ExpiresAt int64       `json:"exp"`
+	NotBefore int64       `json:"nbf,omitempty"`
+	IssuedAt  int64       `json:"iat,omitempty"`
+}
+
+// JWTAudience is a JWT "aud" claim, which may be a string or a list
+type JWTAudience []string
+
+func (a JWTAudience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+func (a *JWTAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = JWTAudience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// jwtHeader is the only JWT header JWTAuthenticator accepts
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SignJWT returns an HS256-signed JWT for claims, e.g. to issue tokens to
+// agents
+func SignJWT(secret []byte, claims JWTClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + jwtSignature(secret, signingInput), nil
+}
+
+func jwtSignature(secret []byte, signingInput string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// JWTAuthenticator accepts HS256-signed JWT bearer tokens. The subject
+// becomes the principal name, and tokens must expire.
+type JWTAuthenticator struct {
+	Secret   []byte
+	Issuer   string        // Required "iss", if set
+	Audience string        // Required "aud" entry, if set
+	Leeway   time.Duration // Allowed clock skew for "exp" and "nbf"
+
+	now func() time.Time // Overridden in tests
+}
+
+// Authenticate verifies the request's bearer JWT
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := a.verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %v", err)
+	}
+	return &Principal{Name: claims.Subject, Method: AuthJWT}, nil
+}
+
+// verify checks a JWT's signature and claims
+func (a *JWTAuthenticator) verify(token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed JWT")
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("malformed JWT header")
+	}
+	var fields struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &fields); err != nil || fields.Algorithm != "HS256" {
+		return nil, errors.New("unsupported JWT algorithm")
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(jwtSignature(a.Secret, parts[0]+"."+parts[1]))) {
+		return nil, errors.New("bad signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed JWT payload")
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("malformed JWT claims")
+	}
+
+	now := time.Now()
+	if a.now != nil {
+		now = a.now()
+	}
+	switch {
+	case claims.Subject == "":
+		return nil, errors.New("missing subject")
+	case claims.ExpiresAt == 0:
+		return nil, errors.New("missing expiry")
+	case now.After(time.Unix(claims.ExpiresAt, 0).Add(a.Leeway)):
+		return nil, errors.New("expired")
+	case claims.NotBefore != 0 && now.Before(time.Unix(claims.NotBefore, 0).Add(-a.Leeway)):
+		return nil, errors.New("not valid yet")
+	case a.Issuer != "" && claims.Issuer != a.Issuer:
+		return nil, errors.New("wrong issuer")
+	}
+	if a.Audience != "" {
+		found := false
+		for _, aud := range claims.Audience {
+			found = found || aud == a.Audience
+		}
+		if !found {
+			return nil, errors.New("wrong audience")
+		}
+	}
+	return &claims, nil
+}
+
+// MTLSAuthenticator accepts clients that present a certificate the TLS
+// listener verified. The certificate's common name, or else its first DNS
+// or email name, becomes the principal name.
+type MTLSAuthenticator struct{}
+
+// Authenticate reads the verified client certificate
+func (MTLSAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, errors.New("client certificate required")
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	name := cert.Subject.CommonName
+	if name == "" && len(cert.DNSNames) > 0 {
+		name = cert.DNSNames[0]
+	}
+	if name == "" && len(cert.EmailAddresses) > 0 {
+		name = cert.EmailAddresses[0]
+	}
+	if name == "" {
+		return nil, errors.New("client certificate has no name")
+	}
+	return &Principal{Name: name, Method: AuthMTLS}, nil
+}
@@ -0,0 +1,335 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// writeTokenFile writes a token file for alice and bob
+func writeTokenFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens")
+	content := "# name token\nalice alice-token\n\nbob   bob-token\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+// bearerRequest builds a request with an optional bearer token
+func bearerRequest(method, path, token, body string) *http.Request {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+// doRESTAs is doREST with a bearer token
+func doRESTAs(t *testing.T, handler http.Handler, token, method, path, body string, out any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, bearerRequest(method, path, token, body))
+	if out != nil && rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s returned invalid JSON %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// asPrincipal returns a context authenticated as name
+func asPrincipal(name string) context.Context {
+	return context.WithValue(context.Background(), principalKey{}, &Principal{Name: name, Method: AuthToken})
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	auth, err := LoadTokenFile(writeTokenFile(t))
+	if err != nil {
+		t.Fatalf("LoadTokenFile failed: %v", err)
+	}
+
+	principal, err := auth.Authenticate(bearerRequest("GET", "/", "bob-token", ""))
+	if err != nil || principal.Name != "bob" || principal.Method != AuthToken {
+		t.Errorf("Expected bob, got %+v, %v", principal, err)
+	}
+	for _, token := range []string{"", "alice-token-x", "ALICE-TOKEN"} {
+		if _, err := auth.Authenticate(bearerRequest("GET", "/", token, "")); err == nil {
+			t.Errorf("Expected token %q to be rejected", token)
+		}
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad")
+	for _, content := range []string{"alice\n", "alice a\nbob a\n", "# only comments\n"} {
+		os.WriteFile(bad, []byte(content), 0o600)
+		if _, err := LoadTokenFile(bad); err == nil {
+			t.Errorf("Expected token file %q to be rejected", content)
+		}
+	}
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	secret := []byte("jwt-secret")
+	now := time.Unix(1_800_000_000, 0)
+	auth := &JWTAuthenticator{Secret: secret, Issuer: "lobby", Audience: "tictactoe", Leeway: time.Minute, now: func() time.Time { return now }}
+
+	valid := JWTClaims{
+		Subject:   "agent-7",
+		Issuer:    "lobby",
+		Audience:  JWTAudience{"other", "tictactoe"},
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+	token, err := SignJWT(secret, valid)
+	if err != nil {
+		t.Fatalf("SignJWT failed: %v", err)
+	}
+	principal, err := auth.Authenticate(bearerRequest("GET", "/", token, ""))
+	if err != nil || principal.Name != "agent-7" || principal.Method != AuthJWT {
+		t.Fatalf("Expected agent-7, got %+v, %v", principal, err)
+	}
+
+	sign := func(change func(*JWTClaims)) string {
+		claims := valid
+		change(&claims)
+		token, _ := SignJWT(secret, claims)
+		return token
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		strings.Split(token, ".")[1] + "."
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"wrong secret", func() string { t, _ := SignJWT([]byte("other"), valid); return t }(), "bad signature"},
+		{"unsigned", unsigned, "unsupported JWT algorithm"},
+		{"malformed", "not-a-jwt", "malformed"},
+		{"expired", sign(func(c *JWTClaims) { c.ExpiresAt = now.Add(-2 * time.Minute).Unix() }), "expired"},
+		{"within leeway", sign(func(c *JWTClaims) { c.ExpiresAt = now.Add(-30 * time.Second).Unix() }), ""},
+		{"no expiry", sign(func(c *JWTClaims) { c.ExpiresAt = 0 }), "missing expiry"},
+		{"not yet valid", sign(func(c *JWTClaims) { c.NotBefore = now.Add(time.Hour).Unix() }), "not valid yet"},
+		{"no subject", sign(func(c *JWTClaims) { c.Subject = "" }), "missing subject"},
+		{"wrong issuer", sign(func(c *JWTClaims) { c.Issuer = "elsewhere" }), "wrong issuer"},
+		{"wrong audience", sign(func(c *JWTClaims) { c.Audience = JWTAudience{"other"} }), "wrong audience"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.Authenticate(bearerRequest("GET", "/", tt.token, ""))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected the token to be accepted, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if _, err := (MTLSAuthenticator{}).Authenticate(req); err == nil {
+		t.Error("Expected a plaintext request to be rejected")
+	}
+
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+		{Subject: pkix.Name{CommonName: "build-agent"}},
+	}}}
+	principal, err := (MTLSAuthenticator{}).Authenticate(req)
+	if err != nil || principal.Name != "build-agent" || principal.Method != AuthMTLS {
+		t.Errorf("Expected build-agent, got %+v, %v", principal, err)
+	}
+
+	req.TLS.VerifiedChains[0][0] = &x509.Certificate{DNSNames: []string{"bot.example.com"}}
+	if principal, _ := (MTLSAuthenticator{}).Authenticate(req); principal == nil || principal.Name != "bot.example.com" {
+		t.Errorf("Expected the DNS name as the principal, got %+v", principal)
+	}
+}
+
+func TestRESTOwnershipAndSeats(t *testing.T) {
+	server := NewTicTacToeServer()
+	auth, _ := LoadTokenFile(writeTokenFile(t))
+	server.EnableAuth(auth)
+	api := server.authenticate(server.RESTHandler())
+
+	if status := doRESTAs(t, api, "", "GET", "/games", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", status)
+	}
+
+	var view GameView
+	if status := doRESTAs(t, api, "alice-token", "POST", "/games", `{"game_id":"owned"}`, &view); status != http.StatusCreated || view.Owner != "alice" {
+		t.Fatalf("Expected alice to own the game, got %d: %+v", status, view)
+	}
+	view = GameView{}
+	if status := doRESTAs(t, api, "alice-token", "POST", "/games/owned/moves", `{"position":"B2"}`, &view); status != http.StatusOK || view.Seats["X"] != "alice" {
+		t.Fatalf("Expected alice to hold X, got %d: %+v", status, view)
+	}
+
+	// Bob takes O, but cannot play X or delete alice's game
+	if status := doRESTAs(t, api, "bob-token", "POST", "/games/owned/moves", `{"position":"A1"}`, nil); status != http.StatusOK {
+		t.Errorf("Expected bob to play O, got %d", status)
+	}
+	if status := doRESTAs(t, api, "bob-token", "POST", "/games/owned/moves", `{"position":"C3","player":"X"}`, nil); status != http.StatusForbidden {
+		t.Errorf("Expected bob to be refused X, got %d", status)
+	}
+	if status := doRESTAs(t, api, "bob-token", "DELETE", "/games/owned", "", nil); status != http.StatusForbidden {
+		t.Errorf("Expected bob to be refused deletion, got %d", status)
+	}
+	if status := doRESTAs(t, api, "alice-token", "DELETE", "/games/owned", "", nil); status != http.StatusNoContent {
+		t.Errorf("Expected alice to delete her game, got %d", status)
+	}
+}
+
+func TestToolOwnershipAndSeats(t *testing.T) {
+	server := NewTicTacToeServer()
+	alice, bob := asPrincipal("alice"), asPrincipal("bob")
+
+	call := func(ctx context.Context, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		result, err := handler(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	call(alice, server.handleNewGame, map[string]interface{}{"game_id": "duel"})
+	if g, _ := server.engine.Snapshot("duel"); g.Owner != "alice" {
+		t.Fatalf("Expected alice to own the game, got %q", g.Owner)
+	}
+	if result := call(bob, server.handleNewGame, map[string]interface{}{"game_id": "duel"}); !result.IsError {
+		t.Error("Expected bob to be refused replacing alice's game")
+	}
+
+	call(alice, server.handleMakeMove, map[string]interface{}{"game_id": "duel", "position": "B2", "player": "X"})
+	call(bob, server.handleMakeMove, map[string]interface{}{"game_id": "duel", "position": "A1", "player": "O"})
+	result := call(bob, server.handleMakeMove, map[string]interface{}{"game_id": "duel", "position": "C3", "player": "X"})
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "held by alice") {
+		t.Errorf("Expected bob to be refused X, got %q", getTextFromResult(result))
+	}
+	if result := call(bob, server.handleResetGame, map[string]interface{}{"game_id": "duel"}); !result.IsError {
+		t.Error("Expected bob to be refused a reset")
+	}
+
+	// Unauthenticated local callers, such as stdio, are not restricted
+	if result := call(context.Background(), server.handleMakeMove, map[string]interface{}{"game_id": "duel", "position": "C3", "player": "X"}); result.IsError {
+		t.Errorf("Expected an unauthenticated move to succeed, got %q", getTextFromResult(result))
+	}
+
+	// Hints are for the principal holding the side to move, O here
+	result = call(alice, server.handleGetHint, map[string]interface{}{"game_id": "duel"})
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "held by bob") {
+		t.Errorf("Expected alice to be refused a hint for O, got %q", getTextFromResult(result))
+	}
+	if result := call(bob, server.handleGetHint, map[string]interface{}{"game_id": "duel"}); result.IsError {
+		t.Errorf("Expected bob to get a hint, got %q", getTextFromResult(result))
+	}
+
+	// An illegal move does not bind the seat
+	call(alice, server.handleNewGame, map[string]interface{}{"game_id": "fresh"})
+	call(bob, server.handleMakeMove, map[string]interface{}{"game_id": "fresh", "position": "Z9", "player": "X"})
+	call(bob, server.handleMakeMove, map[string]interface{}{"game_id": "fresh", "position": "B2", "player": "O"})
+	if g, _ := server.engine.Snapshot("fresh"); len(g.Seats) != 0 {
+		t.Errorf("Expected no seats after illegal moves, got %v", g.Seats)
+	}
+
+	// Games created without authentication cannot be replaced or reset by
+	// authenticated callers
+	call(context.Background(), server.handleNewGame, map[string]interface{}{"game_id": "local"})
+	for _, handler := range []func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error){
+		server.handleNewGame, server.handleGetPuzzle, server.handleResetGame,
+	} {
+		if result := call(alice, handler, map[string]interface{}{"game_id": "local"}); !result.IsError {
+			t.Errorf("Expected alice to be refused the unowned game, got %q", getTextFromResult(result))
+		}
+	}
+
+	// Puzzles and imports are owned from the start
+	call(alice, server.handleGetPuzzle, map[string]interface{}{"game_id": "puzzle"})
+	record := "[Game \"imported\"]\n\n1. B2 A1 *\n"
+	call(alice, server.handleImportGame, map[string]interface{}{"record": record})
+	for _, id := range []string{"puzzle", "imported"} {
+		if g, err := server.engine.Snapshot(id); err != nil || g.Owner != "alice" {
+			t.Errorf("Expected alice to own %s, got %v %v", id, g, err)
+		}
+	}
+}
+
+func TestSessionBoundToPrincipal(t *testing.T) {
+	server := NewTicTacToeServer()
+	session := newFakeSession("alice-session")
+	if err := server.mcpServer.RegisterSession(asPrincipal("alice"), session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+
+	handler := server.checkSessionPrincipal(server.handleNewGame)
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"game_id": "hijack"}}}
+
+	result, _ := handler(server.mcpServer.WithContext(asPrincipal("bob"), session), request)
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "another client") {
+		t.Errorf("Expected bob to be refused alice's session, got %q", getTextFromResult(result))
+	}
+	result, _ = handler(server.mcpServer.WithContext(asPrincipal("alice"), session), request)
+	if result.IsError {
+		t.Errorf("Expected alice to use her own session, got %q", getTextFromResult(result))
+	}
+
+	server.mcpServer.UnregisterSession(context.Background(), session.id)
+	if _, ok := server.sessions.principals.Load(session.id); ok {
+		t.Error("Expected the binding to be dropped with the session")
+	}
+}
+
+func TestServeRequiresAuth(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableREST()
+	auth, _ := LoadTokenFile(writeTokenFile(t))
+	server.EnableAuth(auth)
+	addr := freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startServe(t, ctx, server, ServeOptions{Transports: []string{TransportHTTP, TransportWebSocket}, Addr: addr})
+
+	for _, path := range []string{"/mcp", "/ws", "/games"} {
+		resp, err := http.Get("http://" + addr + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("Expected GET %s to need authentication, got %d", path, resp.StatusCode)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "http://"+addr+"/games", nil)
+	req.Header.Set("Authorization", "Bearer alice-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /games failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected an authenticated request to succeed, got %d", resp.StatusCode)
+	}
+}
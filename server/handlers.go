@@ -2,10 +2,13 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"mcp-tic-tac-toe/game"
 )
 
@@ -21,11 +24,31 @@ func (s *TicTacToeServer) handleNewGame(ctx context.Context, request mcp.CallToo
 		gameID = generateGameID()
 	}
 
-	// Create the game
-	gameState := s.engine.CreateGame(gameID)
+	// Create the game. Only the owner may replace an existing one, which
+	// the engine checks under its lock.
+	gameState, err := s.engine.CreateGameWithOptions(gameID, game.GameOptions{
+		Players: map[game.Player]string{
+			game.PlayerX: request.GetString("player_x", ""),
+			game.PlayerO: request.GetString("player_o", ""),
+		},
+		FEN:   request.GetString("position", ""),
+		Owner: ownerFor(ctx),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create game: %v", err)), nil
+	}
 
 	response := fmt.Sprintf("New game created with ID: %s\nStarting player: %s\nInitial board:\n%s",
 		gameState.GameID, gameState.CurrentPlayer, gameState.Board.String())
+	if gameState.StartFEN != "" {
+		response += fmt.Sprintf("Starting position: %s\n", gameState.StartFEN)
+		switch gameState.Status {
+		case game.StatusWon:
+			response += fmt.Sprintf("Game Over! %s has already won in this position", gameState.Winner)
+		case game.StatusDraw:
+			response += "Game Over! The position is already a draw"
+		}
+	}
 
 	return mcp.NewToolResultText(response), nil
 }
@@ -65,8 +88,9 @@ func (s *TicTacToeServer) handleMakeMove(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultError("Player must be 'X' or 'O'"), nil
 	}
 
-	// Make the move
-	gameState, err := s.engine.MakeMove(gameID, position, player)
+	// Make the move. Authenticated callers may only play the mark they
+	// first played.
+	gameState, err := s.engine.MakeMoveAs(gameID, position, player, ownerFor(ctx))
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Move failed: %v", err)), nil
 	}
@@ -97,7 +121,7 @@ func (s *TicTacToeServer) handleGetBoard(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultError("game_id is required"), nil
 	}
 
-	gameState, err := s.engine.GetGame(gameID)
+	gameState, err := s.engine.Snapshot(gameID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Game not found: %v", err)), nil
 	}
@@ -115,7 +139,7 @@ func (s *TicTacToeServer) handleGetStatus(ctx context.Context, request mcp.CallT
 		return mcp.NewToolResultError("game_id is required"), nil
 	}
 
-	gameState, err := s.engine.GetGame(gameID)
+	gameState, err := s.engine.Snapshot(gameID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Game not found: %v", err)), nil
 	}
@@ -142,6 +166,10 @@ func (s *TicTacToeServer) handleResetGame(ctx context.Context, request mcp.CallT
 		return mcp.NewToolResultError("game_id is required"), nil
 	}
 
+	if err := s.checkOwner(ctx, gameID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Reset failed: %v", err)), nil
+	}
+
 	gameState, err := s.engine.ResetGame(gameID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Reset failed: %v", err)), nil
@@ -186,31 +214,321 @@ func (s *TicTacToeServer) handleAnalyzePosition(ctx context.Context, request mcp
 		return mcp.NewToolResultError("game_id is required"), nil
 	}
 
-	analysis, err := s.engine.AnalyzePosition(gameID)
+	// Analyze a snapshot, so the analysis and the board shown agree
+	gameState, err := s.engine.Snapshot(gameID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Analysis failed: %v", err)), nil
 	}
+	analysis := game.AnalyzeGame(gameState)
 
-	// Get additional details for enhanced analysis
-	gameState, err := s.engine.GetGame(gameID)
+	structured, err := json.MarshalIndent(analysis, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Game not found: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Analysis failed: %v", err)), nil
 	}
 
 	response := fmt.Sprintf("Position Analysis for Game %s:\n%s\n\nCurrent board:\n%s",
 		gameID, analysis, gameState.Board.String())
 
-	return mcp.NewToolResultText(response), nil
+	result := mcp.NewToolResultText(response)
+	result.Content = append(result.Content, mcp.NewTextContent(string(structured)))
+	return result, nil
 }
 
-// handleListGames returns all active game IDs
+// handleListGames returns a filtered, sorted page of game summaries
 func (s *TicTacToeServer) handleListGames(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	gameIDs := s.engine.ListGames()
+	filter := game.GameFilter{
+		Status:  game.GameStatus(request.GetString("status", "")),
+		Variant: game.Variant(request.GetString("variant", "")),
+		Player:  request.GetString("player", ""),
+		SortBy:  game.SortField(request.GetString("sort", "")),
+		Limit:   request.GetInt("limit", game.DefaultPageSize),
+		Cursor:  request.GetString("cursor", ""),
+	}
+
+	if createdAfter := request.GetString("created_after", ""); createdAfter != "" {
+		t, err := time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid created_after: %v", err)), nil
+		}
+		filter.CreatedAfter = t
+	}
 
-	if len(gameIDs) == 0 {
+	switch request.GetString("order", "desc") {
+	case "asc":
+		filter.Ascending = true
+	case "desc":
+	default:
+		return mcp.NewToolResultError("order must be 'asc' or 'desc'"), nil
+	}
+
+	page, err := s.engine.QueryGames(filter)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("List failed: %v", err)), nil
+	}
+
+	if page.Total == 0 {
 		return mcp.NewToolResultText("No active games"), nil
 	}
 
-	response := fmt.Sprintf("Active games (%d): %s", len(gameIDs), strings.Join(gameIDs, ", "))
+	var response strings.Builder
+	fmt.Fprintf(&response, "Active games (%d):\n", page.Total)
+	for _, summary := range page.Games {
+		response.WriteString(formatGameSummary(summary))
+		response.WriteString("\n")
+	}
+	if page.NextCursor != "" {
+		fmt.Fprintf(&response, "Showing %d of %d. Next cursor: %s", len(page.Games), page.Total, page.NextCursor)
+	}
+
+	return mcp.NewToolResultText(strings.TrimSuffix(response.String(), "\n")), nil
+}
+
+// formatGameSummary renders a single list_games entry
+func formatGameSummary(summary game.GameSummary) string {
+	status := string(summary.Status)
+	if summary.Status == game.StatusWon {
+		status = fmt.Sprintf("won by %s", summary.Winner)
+	}
+
+	players := fmt.Sprintf("X: %s, O: %s", playerName(summary.Players, game.PlayerX), playerName(summary.Players, game.PlayerO))
+
+	return fmt.Sprintf("- %s | %s | %s | moves: %d | %s | created: %s | updated: %s",
+		summary.GameID, status, summary.Variant, summary.MoveCount, players,
+		summary.CreatedAt.UTC().Format(time.RFC3339), summary.UpdatedAt.UTC().Format(time.RFC3339))
+}
+
+// playerName returns the participant name for a mark, or "-" when unset
+func playerName(players map[game.Player]string, player game.Player) string {
+	if name := players[player]; name != "" {
+		return name
+	}
+	return "-"
+}
+
+// handleExportGame returns the game record for a game
+func (s *TicTacToeServer) handleExportGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	gameID, err := request.RequireString("game_id")
+	if err != nil {
+		return mcp.NewToolResultError("game_id is required"), nil
+	}
+
+	record, err := s.engine.ExportGame(gameID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Export failed: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(record), nil
+}
+
+// handleImportGame creates a game by replaying a game record
+func (s *TicTacToeServer) handleImportGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	text, err := request.RequireString("record")
+	if err != nil {
+		return mcp.NewToolResultError("record is required"), nil
+	}
+
+	record, err := game.ParseRecord(text)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record: %v", err)), nil
+	}
+
+	gameID := request.GetString("game_id", "")
+	if gameID == "" && record.Tags[game.TagGame] == "" {
+		gameID = generateGameID()
+	}
+
+	gameState, err := s.engine.ImportGameAs(gameID, record, ownerFor(ctx))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Import failed: %v", err)), nil
+	}
+
+	response := fmt.Sprintf("Imported game %s (%d moves, result %s)\nBoard:\n%s",
+		gameState.GameID, gameState.MoveCount, game.ResultFor(gameState), gameState.Board.String())
+	if !gameState.IsGameOver() {
+		response += fmt.Sprintf("Next player: %s", gameState.CurrentPlayer)
+	}
+
 	return mcp.NewToolResultText(response), nil
 }
+
+// handleGetPuzzle starts a new game at a puzzle position
+func (s *TicTacToeServer) handleGetPuzzle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var puzzle game.Puzzle
+	var err error
+	if puzzleID := request.GetString("puzzle_id", ""); puzzleID != "" {
+		puzzle, err = game.FindPuzzle(puzzleID)
+	} else {
+		puzzle, err = game.RandomPuzzle(game.PuzzleKind(request.GetString("kind", "")), request.GetInt("difficulty", 0))
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No puzzle available: %v", err)), nil
+	}
+
+	gameID := request.GetString("game_id", "")
+	if gameID == "" {
+		gameID = generateGameID()
+	}
+
+	// Only the owner may replace an existing game, which the engine checks
+	opts := game.PuzzleOptions(puzzle)
+	opts.Owner = ownerFor(ctx)
+	gameState, err := s.engine.CreateGameWithOptions(gameID, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create puzzle game: %v", err)), nil
+	}
+
+	response := fmt.Sprintf("Puzzle %s (%s, difficulty %d/%d)\nGame ID: %s\n%s\n\nBoard:\n%s\nAnswer with check_puzzle_answer.",
+		puzzle.ID, puzzle.Kind, puzzle.Difficulty, game.MaxPuzzleDifficulty,
+		gameState.GameID, puzzle.Prompt, gameState.Board.String())
+
+	return mcp.NewToolResultText(response), nil
+}
+
+// handleCheckPuzzleAnswer checks a proposed puzzle move
+func (s *TicTacToeServer) handleCheckPuzzleAnswer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	gameID, err := request.RequireString("game_id")
+	if err != nil {
+		return mcp.NewToolResultError("game_id is required"), nil
+	}
+
+	positionStr, err := request.RequireString("position")
+	if err != nil {
+		return mcp.NewToolResultError("position is required"), nil
+	}
+
+	position, err := game.ParsePosition(positionStr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid position: %v", err)), nil
+	}
+
+	correct, gameState, err := s.engine.CheckPuzzleAnswerAs(gameID, position, ownerFor(ctx))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Check failed: %v", err)), nil
+	}
+
+	if !correct {
+		response := fmt.Sprintf("Not quite: %s is not the answer (attempt %d). Try again.\n\nBoard:\n%s",
+			positionStr, gameState.PuzzleAttempts, gameState.Board.String())
+		return mcp.NewToolResultText(response), nil
+	}
+
+	solutions := make([]string, len(gameState.Puzzle.Solutions))
+	for i, pos := range gameState.Puzzle.Solutions {
+		solutions[i] = pos.String()
+	}
+
+	response := fmt.Sprintf("Correct! %s solves puzzle %s in %d attempt(s). Accepted answers: %s\n\nBoard:\n%s",
+		positionStr, gameState.Puzzle.ID, gameState.PuzzleAttempts, strings.Join(solutions, ", "), gameState.Board.String())
+	if gameState.IsGameOver() {
+		response += "\nGame Over!"
+		if gameState.Status == game.StatusWon {
+			response += fmt.Sprintf(" %s wins!", gameState.Winner)
+		}
+	} else {
+		response += fmt.Sprintf("\nPlay continues with make_move. Next player: %s", gameState.CurrentPlayer)
+	}
+
+	return mcp.NewToolResultText(response), nil
+}
+
+// handleGetHint returns a hint for the player to move
+func (s *TicTacToeServer) handleGetHint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	gameID, err := request.RequireString("game_id")
+	if err != nil {
+		return mcp.NewToolResultError("game_id is required"), nil
+	}
+
+	hint, err := s.engine.GetHintAs(gameID, request.GetInt("level", game.HintNudge), ownerFor(ctx))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Hint unavailable: %v", err)), nil
+	}
+
+	response := fmt.Sprintf("Hint (level %d): %s\nHint points remaining: %d", hint.Level, hint.Text, hint.Remaining)
+	return mcp.NewToolResultText(response), nil
+}
+
+// handleReviewGame annotates the moves of a finished game
+func (s *TicTacToeServer) handleReviewGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	gameID, err := request.RequireString("game_id")
+	if err != nil {
+		return mcp.NewToolResultError("game_id is required"), nil
+	}
+
+	review, err := s.engine.ReviewGame(gameID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Review failed: %v", err)), nil
+	}
+
+	structured, err := json.MarshalIndent(review, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Review failed: %v", err)), nil
+	}
+
+	result := mcp.NewToolResultText(review.String())
+	result.Content = append(result.Content, mcp.NewTextContent(string(structured)))
+	return result, nil
+}
+
+// handleOpeningExplorer aggregates stored games by opening sequence
+func (s *TicTacToeServer) handleOpeningExplorer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	record, err := game.ParseRecord(strings.ReplaceAll(request.GetString("moves", ""), ",", " "))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid moves: %v", err)), nil
+	}
+
+	report, err := s.engine.ExploreOpening(record.Moves)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Opening explorer failed: %v", err)), nil
+	}
+
+	structured, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Opening explorer failed: %v", err)), nil
+	}
+
+	result := mcp.NewToolResultText(report.String())
+	result.Content = append(result.Content, mcp.NewTextContent(string(structured)))
+	return result, nil
+}
+
+// handleWatchGame subscribes the calling session to a game's events
+func (s *TicTacToeServer) handleWatchGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	gameID, err := request.RequireString("game_id")
+	if err != nil {
+		return mcp.NewToolResultError("game_id is required"), nil
+	}
+
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return mcp.NewToolResultError("Watch failed: watching requires a client session"), nil
+	}
+
+	gameState, err := s.engine.Snapshot(gameID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Watch failed: %v", err)), nil
+	}
+
+	count := s.spectators.watch(gameID, session.SessionID())
+
+	status := string(gameState.Status)
+	if gameState.Status == game.StatusWon {
+		status = fmt.Sprintf("won by %s", gameState.Winner)
+	}
+	response := fmt.Sprintf("Watching game %s (%d spectator(s)). Moves will arrive as %s notifications.\nStatus: %s\n%s",
+		gameID, count, GameEventNotification, status, gameState.Board.String())
+	return mcp.NewToolResultText(response), nil
+}
+
+// handleUnwatchGame unsubscribes the calling session from a game
+func (s *TicTacToeServer) handleUnwatchGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	gameID, err := request.RequireString("game_id")
+	if err != nil {
+		return mcp.NewToolResultError("game_id is required"), nil
+	}
+
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || !s.spectators.unwatch(gameID, session.SessionID()) {
+		return mcp.NewToolResultError(fmt.Sprintf("Unwatch failed: not watching game %s", gameID)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Stopped watching game %s", gameID)), nil
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"mcp-tic-tac-toe/game"
+)
+
+// LimitOptions caps how hard a single client can drive the server. A client
+// is an authenticated principal, else an MCP session, else (for the REST
+// API) a remote IP address.
+type LimitOptions struct {
+	Rate         float64 // Sustained tool calls per second per client; 0 for no limit
+	Burst        int     // Calls a client may make at once before Rate applies
+	MaxOpenGames int     // Games per client until they are deleted; 0 for no limit
+}
+
+// EnableLimits applies rate limits and open-game quotas to tool calls and
+// REST requests
+func (s *TicTacToeServer) EnableLimits(opts LimitOptions) {
+	if opts.Rate > 0 {
+		s.rateLimiter = newRateLimiter(opts.Rate, opts.Burst)
+	}
+	if opts.MaxOpenGames > 0 {
+		s.quotas = newGameQuotas(opts.MaxOpenGames, s.engine)
+	}
+}
+
+// creatingTools are the tools that open a game
+var creatingTools = map[string]bool{
+	"new_game":    true,
+	"get_puzzle":  true,
+	"import_game": true,
+}
+
+// clientKey identifies the client making a tool call
+func clientKey(ctx context.Context) string {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return "principal:" + principal.Name
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return "session:" + session.SessionID()
+	}
+	return "local"
+}
+
+// restClientKey identifies the client making a REST request
+func restClientKey(r *http.Request) string {
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		return "principal:" + principal.Name
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// limitToolCalls is tool handler middleware that enforces the rate limit and,
+// for tools that open games, the open-game quota
+func (s *TicTacToeServer) limitToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := clientKey(ctx)
+		if s.rateLimiter != nil {
+			if ok, wait := s.rateLimiter.allow(client); !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("Rate limit exceeded: retry in %.1fs", wait.Seconds())), nil
+			}
+		}
+		if s.quotas == nil || !creatingTools[request.Params.Name] {
+			return next(ctx, request)
+		}
+
+		gameID, ok := createdGameIDFromContext(ctx)
+		if !ok {
+			gameID = createdGameID(&request)
+		}
+		if err := s.quotas.reserve(client, gameID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Open game quota reached: %v", err)), nil
+		}
+		result, err := next(ctx, request)
+		s.quotas.settle(client, gameID, err == nil && result != nil && !result.IsError)
+		return result, err
+	}
+}
+
+// createdGameID returns the ID of the game a creating tool call will open.
+// If the caller left it to the server, it chooses one up front and adds it
+// to the arguments, so the quota tracks the game the handler creates.
+func createdGameID(request *mcp.CallToolRequest) string {
+	arguments := request.GetArguments()
+	if gameID, ok := arguments["game_id"].(string); ok && gameID != "" {
+		return gameID
+	}
+
+	var gameID string
+	if request.Params.Name == "import_game" {
+		// Imports default to the record's Game tag
+		if text, ok := arguments["record"].(string); ok {
+			if record, err := game.ParseRecord(text); err == nil {
+				gameID = record.Tags[game.TagGame]
+			}
+		}
+	}
+	if gameID == "" {
+		gameID = generateGameID()
+	}
+	withID := make(map[string]any, len(arguments)+1)
+	for key, value := range arguments {
+		withID[key] = value
+	}
+	withID["game_id"] = gameID
+	request.Params.Arguments = withID
+	return gameID
+}
+
+// createdGameIDKey is the context key for the ID createdGameID chose, so
+// later middleware uses the same one
+type createdGameIDKey struct{}
+
+func withCreatedGameID(ctx context.Context, gameID string) context.Context {
+	return context.WithValue(ctx, createdGameIDKey{}, gameID)
+}
+
+func createdGameIDFromContext(ctx context.Context) (string, bool) {
+	gameID, ok := ctx.Value(createdGameIDKey{}).(string)
+	return gameID, ok
+}
+
+// limitREST wraps a REST handler with the rate limit
+func (s *TicTacToeServer) limitREST(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.rateLimiter != nil {
+			if ok, wait := s.rateLimiter.allow(restClientKey(r)); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// rateLimiter is a token bucket per client
+type rateLimiter struct {
+	rate  float64 // Tokens added per second
+	burst float64 // Bucket size
+
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+	now     func() time.Time // Overridden in tests
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the client's bucket. If it is empty, it reports
+// how long until the next token.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep drops the buckets of clients idle long enough to have refilled,
+// at most once a minute, so departed sessions do not pile up
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < time.Minute {
+		return
+	}
+	l.swept = now
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, bucket := range l.buckets {
+		if now.Sub(bucket.last) >= refill {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// gameQuotas tracks the games each client opened, to cap how many it holds
+// at once. A game counts until it is deleted, finished or not, so importing
+// finished games cannot get around the cap.
+type gameQuotas struct {
+	max    int
+	engine *game.Engine
+
+	mutex   sync.Mutex
+	games   map[string]map[string]bool // Client -> IDs of games it opened
+	pending map[string]int             // Client -> creations in progress
+}
+
+func newGameQuotas(max int, engine *game.Engine) *gameQuotas {
+	return &gameQuotas{
+		max:     max,
+		engine:  engine,
+		games:   make(map[string]map[string]bool),
+		pending: make(map[string]int),
+	}
+}
+
+// reserve holds a slot for a game the client is about to open. Replacing a
+// game the client already opened needs no new slot.
+func (q *gameQuotas) reserve(client, gameID string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	open := q.open(client)
+	if gameID != "" && q.games[client][gameID] {
+		open--
+	}
+	if open+q.pending[client] >= q.max {
+		return fmt.Errorf("%d of %d allowed games are open; delete one first", open, q.max)
+	}
+	q.pending[client]++
+	return nil
+}
+
+// settle releases a reservation, recording the game if it was opened
+func (q *gameQuotas) settle(client, gameID string, opened bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.pending[client]--; q.pending[client] <= 0 {
+		delete(q.pending, client)
+	}
+	if !opened || gameID == "" {
+		return
+	}
+	if q.games[client] == nil {
+		q.games[client] = make(map[string]bool)
+	}
+	q.games[client][gameID] = true
+}
+
+// open counts the client's games, forgetting deleted ones. Callers hold
+// the mutex.
+func (q *gameQuotas) open(client string) int {
+	for gameID := range q.games[client] {
+		if _, err := q.engine.Snapshot(gameID); err != nil {
+			delete(q.games[client], gameID)
+		}
+	}
+	if len(q.games[client]) == 0 {
+		delete(q.games, client)
+		return 0
+	}
+	return len(q.games[client])
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+// callThroughServer sends a tools/call through the MCP server, so that tool
+// middleware runs, and returns the result text and whether it is an error
+func callThroughServer(t *testing.T, server *TicTacToeServer, ctx context.Context, name string, arguments map[string]any) (string, bool) {
+	t.Helper()
+	message, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+		"params": map[string]any{"name": name, "arguments": arguments},
+	})
+	response, _ := json.Marshal(server.mcpServer.HandleMessage(ctx, message))
+
+	var decoded struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(response, &decoded); err != nil || len(decoded.Result.Content) == 0 {
+		t.Fatalf("Unexpected response %s", response)
+	}
+	return decoded.Result.Content[0].Text, decoded.Result.IsError
+}
+
+// sessionContext registers a fake session and returns its context
+func sessionContext(t *testing.T, server *TicTacToeServer, id string) context.Context {
+	t.Helper()
+	session := newFakeSession(id)
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+	return server.mcpServer.WithContext(context.Background(), session)
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.allow("a"); !ok {
+			t.Fatalf("Expected call %d within the burst to pass", i+1)
+		}
+	}
+	ok, wait := limiter.allow("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("Expected the 4th call to wait 500ms, got %v, %v", ok, wait)
+	}
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Error("Expected another client to have its own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Error("Expected a token after waiting")
+	}
+
+	// Idle clients are forgotten once their bucket would be full again
+	now = now.Add(2 * time.Minute)
+	limiter.allow("c")
+	if _, ok := limiter.buckets["a"]; ok {
+		t.Error("Expected idle buckets to be swept")
+	}
+}
+
+func TestToolCallsRateLimited(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableLimits(LimitOptions{Rate: 0.001, Burst: 2})
+	server.engine.CreateGame("busy")
+	ctx := sessionContext(t, server, "hammer")
+
+	for i := 0; i < 2; i++ {
+		if text, isError := callThroughServer(t, server, ctx, "get_board", map[string]any{"game_id": "busy"}); isError {
+			t.Fatalf("Expected call %d to pass, got %q", i+1, text)
+		}
+	}
+	text, isError := callThroughServer(t, server, ctx, "get_board", map[string]any{"game_id": "busy"})
+	if !isError || !strings.Contains(text, "Rate limit exceeded") {
+		t.Errorf("Expected the 3rd call to be limited, got %q", text)
+	}
+
+	other := sessionContext(t, server, "polite")
+	if text, isError := callThroughServer(t, server, other, "get_board", map[string]any{"game_id": "busy"}); isError {
+		t.Errorf("Expected another session to be unaffected, got %q", text)
+	}
+}
+
+func TestOpenGameQuota(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableLimits(LimitOptions{MaxOpenGames: 2})
+	ctx := sessionContext(t, server, "collector")
+
+	callThroughServer(t, server, ctx, "new_game", map[string]any{"game_id": "first"})
+	callThroughServer(t, server, ctx, "new_game", map[string]any{})
+	text, isError := callThroughServer(t, server, ctx, "new_game", map[string]any{})
+	if !isError || !strings.Contains(text, "Open game quota reached: 2 of 2") {
+		t.Fatalf("Expected the quota to refuse a 3rd game, got %q", text)
+	}
+	if len(server.engine.ListGames()) != 2 {
+		t.Errorf("Expected the refused call not to reach the engine, got %d games", len(server.engine.ListGames()))
+	}
+
+	// Replacing one's own game needs no new slot
+	if text, isError := callThroughServer(t, server, ctx, "new_game", map[string]any{"game_id": "first"}); isError {
+		t.Errorf("Expected replacing an own game to pass, got %q", text)
+	}
+	if _, isError := callThroughServer(t, server, ctx, "get_puzzle", map[string]any{}); !isError {
+		t.Error("Expected puzzles to count against the quota")
+	}
+
+	// Finishing a game keeps its slot; deleting it frees the slot
+	for _, move := range []struct{ pos, player string }{{"A1", "X"}, {"B1", "O"}, {"A2", "X"}, {"B2", "O"}, {"A3", "X"}} {
+		pos, _ := game.ParsePosition(move.pos)
+		server.engine.MakeMove("first", pos, game.Player(move.player))
+	}
+	if _, isError := callThroughServer(t, server, ctx, "get_puzzle", map[string]any{}); !isError {
+		t.Error("Expected a finished game to keep its slot")
+	}
+	if text, isError := callThroughServer(t, server, ctx, "reset_game", map[string]any{"game_id": "first"}); isError {
+		t.Errorf("Expected resetting an own game to need no slot, got %q", text)
+	}
+	server.engine.DeleteGame("first")
+	if text, isError := callThroughServer(t, server, ctx, "get_puzzle", map[string]any{}); isError {
+		t.Errorf("Expected a deleted game to free a slot, got %q", text)
+	}
+
+	// Imports without an ID or Game tag are counted too
+	importer := sessionContext(t, server, "importer")
+	for i := 0; i < 2; i++ {
+		if text, isError := callThroughServer(t, server, importer, "import_game", map[string]any{"record": "1. B2 *"}); isError {
+			t.Fatalf("Import %d failed: %q", i+1, text)
+		}
+	}
+	if _, isError := callThroughServer(t, server, importer, "import_game", map[string]any{"record": "1. B2 *"}); !isError {
+		t.Error("Expected untagged imports to count against the quota")
+	}
+
+	// Other clients have their own quota
+	other := sessionContext(t, server, "newcomer")
+	if text, isError := callThroughServer(t, server, other, "new_game", map[string]any{}); isError {
+		t.Errorf("Expected another session to have its own quota, got %q", text)
+	}
+}
+
+func TestOpenGameQuotaCountsFinishedImports(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableLimits(LimitOptions{MaxOpenGames: 2})
+	ctx := sessionContext(t, server, "archivist")
+
+	finished := "1. B2 A1 2. C2 A2 3. C1 A3 0-1"
+	for i := 0; i < 2; i++ {
+		if text, isError := callThroughServer(t, server, ctx, "import_game", map[string]any{"record": finished}); isError {
+			t.Fatalf("Import %d failed: %q", i+1, text)
+		}
+	}
+	text, isError := callThroughServer(t, server, ctx, "import_game", map[string]any{"record": finished})
+	if !isError || !strings.Contains(text, "Open game quota reached") {
+		t.Errorf("Expected finished imports to count against the quota, got %q", text)
+	}
+	fen := "XXX/OO./... o"
+	if text, isError := callThroughServer(t, server, ctx, "new_game", map[string]any{"position": fen}); !isError || !strings.Contains(text, "Open game quota reached") {
+		t.Errorf("Expected the quota to refuse a game from a finished position, got %q", text)
+	}
+	if len(server.engine.ListGames()) != 2 {
+		t.Errorf("Expected 2 games, got %d", len(server.engine.ListGames()))
+	}
+}
+
+func TestRESTLimits(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableLimits(LimitOptions{Rate: 0.001, Burst: 3, MaxOpenGames: 1})
+	api := server.RESTHandler()
+
+	if status := doREST(t, api, "POST", "/games", `{"game_id":"one"}`, nil); status != http.StatusCreated {
+		t.Fatalf("Expected the first game to be created, got %d", status)
+	}
+	var body map[string]string
+	if status := doREST(t, api, "POST", "/games", `{"game_id":"two"}`, &body); status != http.StatusTooManyRequests || !strings.Contains(body["error"], "quota") {
+		t.Errorf("Expected the quota to refuse a 2nd game, got %d: %v", status, body)
+	}
+
+	doREST(t, api, "GET", "/games", "", nil)
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest("GET", "/games", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After once the burst is spent, got %d", rec.Code)
+	}
+}
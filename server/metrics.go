@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"mcp-tic-tac-toe/game"
+)
+
+// Tool call and move outcomes, as metric labels
+const (
+	outcomeOK    = "ok"
+	outcomeError = "error"
+)
+
+// serverMetrics are the server's Prometheus metrics. They are always
+// collected; EnableMetrics only decides whether they are served.
+type serverMetrics struct {
+	registry      metricRegistry
+	toolCalls     *counter   // By tool and outcome
+	toolDuration  *histogram // By tool
+	moveDuration  *histogram // By outcome
+	gamesFinished *counter   // By result
+	sessions      *gauge     // By transport
+	transports    sync.Map   // Session ID -> transport it was registered on
+}
+
+func newServerMetrics(engine *game.Engine) *serverMetrics {
+	m := &serverMetrics{}
+	m.toolCalls = m.registry.newCounter("tictactoe_tool_calls_total",
+		"MCP tool calls by tool and outcome.", "tool", "outcome")
+	m.toolDuration = m.registry.newHistogram("tictactoe_tool_call_duration_seconds",
+		"Time to handle an MCP tool call.", defaultBuckets, "tool")
+	m.moveDuration = m.registry.newHistogram("tictactoe_move_duration_seconds",
+		"Time for the engine to play a move, including waiting for its lock.", defaultBuckets, "outcome")
+	m.registry.newGaugeFunc("tictactoe_games",
+		"Games held by the engine, by status.", "status", func() map[string]float64 {
+			values := make(map[string]float64)
+			for status, count := range engine.CountGames() {
+				values[string(status)] = float64(count)
+			}
+			return values
+		})
+	m.gamesFinished = m.registry.newCounter("tictactoe_games_finished_total",
+		"Games that ended, by result.", "result")
+	m.sessions = m.registry.newGauge("tictactoe_sessions",
+		"Open MCP sessions, by transport.", "transport")
+
+	engine.ObserveMoves(func(elapsed time.Duration, err error) {
+		m.moveDuration.Observe(elapsed.Seconds(), outcome(err == nil))
+	})
+	engine.Events().Subscribe(func(event game.Event) {
+		if event.Type == game.EventGameEnded {
+			m.gamesFinished.Inc(gameResult(event.Game))
+		}
+	})
+	return m
+}
+
+// EnableMetrics serves the Prometheus metrics at /metrics on the HTTP
+// listeners
+func (s *TicTacToeServer) EnableMetrics() {
+	s.serveMetrics = true
+}
+
+// registerMetrics adds the metrics route to a mux, if enabled
+func (s *TicTacToeServer) registerMetrics(mux *http.ServeMux) {
+	if s.serveMetrics {
+		mux.Handle("GET /metrics", &s.metrics.registry)
+	}
+}
+
+// measureToolCalls is tool handler middleware that counts and times tool
+// calls. It is the outermost middleware, so calls refused by the others are
+// counted too.
+func (s *TicTacToeServer) measureToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		tool := request.Params.Name
+		s.metrics.toolDuration.Observe(time.Since(start).Seconds(), tool)
+		s.metrics.toolCalls.Inc(tool, outcome(err == nil && (result == nil || !result.IsError)))
+		return result, err
+	}
+}
+
+// addMetricsHooks counts sessions by transport as they open and close. The
+// transport is recorded when the session is registered, from the context the
+// transport registered it with.
+func (s *TicTacToeServer) addMetricsHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		transport := transportFromContext(ctx)
+		s.metrics.transports.Store(session.SessionID(), transport)
+		s.metrics.sessions.Add(1, transport)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		if transport, ok := s.metrics.transports.LoadAndDelete(session.SessionID()); ok {
+			s.metrics.sessions.Add(-1, transport.(string))
+		}
+	})
+}
+
+// sessionTransport names the transport a session arrived on. Streamable HTTP
+// only registers sessions that open a GET stream, so for the others it falls
+// back to the transport the request context was tagged with.
+func (s *TicTacToeServer) sessionTransport(ctx context.Context, session server.ClientSession) string {
+	if transport, ok := s.metrics.transports.Load(session.SessionID()); ok {
+		return transport.(string)
+	}
+	return transportFromContext(ctx)
+}
+
+// transportKey is the context key for the transport a request arrived on
+type transportKey struct{}
+
+// withTransport tags ctx with the transport a request arrived on
+func withTransport(ctx context.Context, transport string) context.Context {
+	return context.WithValue(ctx, transportKey{}, transport)
+}
+
+// transportFromContext returns the transport ctx was tagged with, or "other"
+func transportFromContext(ctx context.Context) string {
+	if transport, ok := ctx.Value(transportKey{}).(string); ok {
+		return transport
+	}
+	return "other"
+}
+
+// tagTransport tags the requests an MCP transport handler serves with the
+// transport's name
+func tagTransport(transport string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(withTransport(r.Context(), transport)))
+	})
+}
+
+func outcome(ok bool) string {
+	if ok {
+		return outcomeOK
+	}
+	return outcomeError
+}
+
+// gameResult labels how a finished game ended
+func gameResult(g *game.GameState) string {
+	switch {
+	case g.Status == game.StatusDraw:
+		return "draw"
+	case g.Winner == game.PlayerX:
+		return "x_won"
+	default:
+		return "o_won"
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrapeMetrics fetches /metrics from url and returns the body
+func scrapeMetrics(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url + "/metrics")
+	if err != nil {
+		t.Fatalf("Scraping metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", contentType)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+// expectMetric checks that a metric line is present
+func expectMetric(t *testing.T, text, line string) {
+	t.Helper()
+	for _, got := range strings.Split(text, "\n") {
+		if got == line {
+			return
+		}
+	}
+	t.Errorf("Expected metric line %q in:\n%s", line, text)
+}
+
+func TestMetricRegistryText(t *testing.T) {
+	var registry metricRegistry
+	calls := registry.newCounter("calls_total", "Calls made.", "name")
+	calls.Inc(`say "hi"\now`)
+	calls.Inc("b")
+	calls.Inc("b")
+	latency := registry.newHistogram("latency_seconds", "Latency.", []float64{0.1, 1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(5)
+	registry.newGaugeFunc("items", "Items.", "kind", func() map[string]float64 {
+		return map[string]float64{"a": 2}
+	})
+
+	var out bytes.Buffer
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	want := `# HELP calls_total Calls made.
+# TYPE calls_total counter
+calls_total{name="b"} 2
+calls_total{name="say \"hi\"\\now"} 1
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 5.55
+latency_seconds_count 3
+# HELP items Items.
+# TYPE items gauge
+items{kind="a"} 2
+`
+	if out.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for the wrong number of label values")
+		}
+	}()
+	calls.Inc("a", "b")
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableMetrics()
+	httpServer := httptest.NewServer(server.httpMux("/ws", server.WebSocketHandler()))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	callThroughServer(t, server, ctx, "new_game", map[string]any{"game_id": "measured"})
+	for _, move := range []struct{ position, player string }{
+		{"A1", "X"}, {"B1", "O"}, {"A2", "X"}, {"B2", "O"}, {"A3", "X"},
+	} {
+		callThroughServer(t, server, ctx, "make_move", map[string]any{
+			"game_id": "measured", "position": move.position, "player": move.player,
+		})
+	}
+	callThroughServer(t, server, ctx, "make_move", map[string]any{"game_id": "measured", "position": "C1", "player": "O"})
+	callThroughServer(t, server, ctx, "new_game", map[string]any{"game_id": "open"})
+
+	client := dialWebSocket(t, httpServer.URL)
+	defer client.conn.Close()
+	initializeWebSocket(client)
+
+	text := scrapeMetrics(t, httpServer.URL)
+	expectMetric(t, text, `tictactoe_tool_calls_total{tool="new_game",outcome="ok"} 2`)
+	expectMetric(t, text, `tictactoe_tool_calls_total{tool="make_move",outcome="ok"} 5`)
+	expectMetric(t, text, `tictactoe_tool_calls_total{tool="make_move",outcome="error"} 1`)
+	expectMetric(t, text, `tictactoe_tool_call_duration_seconds_count{tool="make_move"} 6`)
+	expectMetric(t, text, `tictactoe_move_duration_seconds_count{outcome="ok"} 5`)
+	expectMetric(t, text, `tictactoe_move_duration_seconds_count{outcome="error"} 1`)
+	expectMetric(t, text, `tictactoe_games{status="ongoing"} 1`)
+	expectMetric(t, text, `tictactoe_games{status="won"} 1`)
+	expectMetric(t, text, `tictactoe_games{status="draw"} 0`)
+	expectMetric(t, text, `tictactoe_games_finished_total{result="x_won"} 1`)
+	expectMetric(t, text, `tictactoe_sessions{transport="ws"} 1`)
+
+	client.conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(text, `tictactoe_sessions{transport="ws"} 0`) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		text = scrapeMetrics(t, httpServer.URL)
+	}
+	expectMetric(t, text, `tictactoe_sessions{transport="ws"} 0`)
+}
+
+func TestMetricsDisabledByDefault(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableREST()
+	httpServer := httptest.NewServer(server.RESTHandler())
+	defer httpServer.Close()
+
+	resp, err := http.Get(httpServer.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 without EnableMetrics, got %d", resp.StatusCode)
+	}
+
+	server.EnableMetrics()
+	metricsServer := httptest.NewServer(server.RESTHandler())
+	defer metricsServer.Close()
+	expectMetric(t, scrapeMetrics(t, metricsServer.URL), "# TYPE tictactoe_games gauge")
+}
+
+func TestSessionTransportRecordedAtRegistration(t *testing.T) {
+	server := NewTicTacToeServer()
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		server.metrics.registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		return recorder.Body.String()
+	}
+
+	session := newFakeSession("sse-session")
+	register := tagTransport(TransportSSE, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := server.mcpServer.RegisterSession(r.Context(), session); err != nil {
+			t.Errorf("RegisterSession failed: %v", err)
+		}
+	}))
+	register.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/sse", nil))
+	expectMetric(t, scrape(), `tictactoe_sessions{transport="sse"} 1`)
+	if got := server.sessionTransport(context.Background(), session); got != TransportSSE {
+		t.Errorf("Expected the session to be recorded as sse, got %q", got)
+	}
+
+	// Unregistering needs no tagged context
+	server.mcpServer.UnregisterSession(context.Background(), session.SessionID())
+	expectMetric(t, scrape(), `tictactoe_sessions{transport="sse"} 0`)
+}
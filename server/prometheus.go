@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// This file implements the small part of the Prometheus text exposition
+// format the server needs: counters, gauges and histograms with labels.
+
+// defaultBuckets are histogram upper bounds in seconds, from 100µs to 10s
+var defaultBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metricRegistry holds metrics and writes them in the text format
+type metricRegistry struct {
+	mutex   sync.Mutex
+	metrics []metric
+}
+
+// metric is one named metric family
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+func (r *metricRegistry) register(m metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText writes every metric in the Prometheus text format
+func (r *metricRegistry) WriteText(w io.Writer) error {
+	r.mutex.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mutex.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// ServeHTTP serves the metrics to a Prometheus scraper
+func (r *metricRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// family is the name, help and labels shared by a metric's series
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (f *family) header(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, strings.ReplaceAll(f.help, "\n", " "))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+}
+
+// key joins label values into a series key
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs formats a series key as {name="value",...}, plus an extra
+// pair if given
+func (f *family) labelPairs(key string, extra ...string) string {
+	var pairs []string
+	if len(f.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, f.labels[i]+`="`+escapeLabel(value)+`"`)
+		}
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, extra[0]+`="`+escapeLabel(extra[1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns a map's keys in order, so output is stable
+func sortedKeys[V any](series map[string]V) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// counter is a value that only goes up
+type counter struct {
+	family
+	mutex  sync.Mutex
+	series map[string]float64
+}
+
+func (r *metricRegistry) newCounter(name, help string, labels ...string) *counter {
+	c := &counter{family: family{name, help, "counter", labels}, series: make(map[string]float64)}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the series with the given label values
+func (c *counter) Inc(values ...string) {
+	key := c.key(values)
+	c.mutex.Lock()
+	c.series[key]++
+	c.mutex.Unlock()
+}
+
+// value returns a series' current value
+func (c *counter) value(values ...string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.series[c.key(values)]
+}
+
+func (c *counter) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.header(w)
+	for _, key := range sortedKeys(c.series) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(key), formatValue(c.series[key]))
+	}
+}
+
+// gauge is a value that goes up and down
+type gauge struct {
+	family
+	mutex  sync.Mutex
+	series map[string]float64
+}
+
+func (r *metricRegistry) newGauge(name, help string, labels ...string) *gauge {
+	g := &gauge{family: family{name, help, "gauge", labels}, series: make(map[string]float64)}
+	r.register(g)
+	return g
+}
+
+// Add changes the series with the given label values by delta
+func (g *gauge) Add(delta float64, values ...string) {
+	key := g.key(values)
+	g.mutex.Lock()
+	g.series[key] += delta
+	g.mutex.Unlock()
+}
+
+func (g *gauge) write(w *bufio.Writer) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.header(w)
+	for _, key := range sortedKeys(g.series) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelPairs(key), formatValue(g.series[key]))
+	}
+}
+
+// gaugeFunc is a gauge whose series are read when scraped
+type gaugeFunc struct {
+	family
+	collect func() map[string]float64 // Label value -> value
+}
+
+func (r *metricRegistry) newGaugeFunc(name, help, label string, collect func() map[string]float64) *gaugeFunc {
+	g := &gaugeFunc{family: family{name, help, "gauge", []string{label}}, collect: collect}
+	r.register(g)
+	return g
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	series := g.collect()
+	g.header(w)
+	for _, key := range sortedKeys(series) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelPairs(key), formatValue(series[key]))
+	}
+}
+
+// histogram counts observations into buckets
+type histogram struct {
+	family
+	buckets []float64
+	mutex   sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 // Per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func (r *metricRegistry) newHistogram(name, help string, buckets []float64, labels ...string) *histogram {
+	h := &histogram{family: family{name, help, "histogram", labels}, buckets: buckets, series: make(map[string]*histogramSeries)}
+	r.register(h)
+	return h
+}
+
+// Observe records a value in the series with the given label values
+func (h *histogram) Observe(v float64, values ...string) {
+	key := h.key(values)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		series.counts[i]++
+	}
+	series.count++
+	series.sum += v
+}
+
+func (h *histogram) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.header(w)
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, "le", "+Inf"), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(key), formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(key), series.count)
+	}
+}
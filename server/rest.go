@@ -0,0 +1,363 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+// maxRequestBody caps REST request bodies
+const maxRequestBody = 64 << 10
+
+// GameView is the REST representation of a game
+type GameView struct {
+	GameID        string            `json:"game_id"`
+	Status        game.GameStatus   `json:"status"`
+	Winner        game.Player       `json:"winner,omitempty"`
+	CurrentPlayer game.Player       `json:"current_player,omitempty"` // Omitted once the game is over
+	Variant       game.Variant      `json:"variant"`
+	Players       map[string]string `json:"players"`
+	Owner         string            `json:"owner,omitempty"` // Authenticated principal that created the game
+	Seats         map[string]string `json:"seats,omitempty"` // Authenticated principals bound to each mark
+	Board         [3][3]string      `json:"board"`           // Rows 1-3, columns A-C; "X", "O" or ""
+	FEN           string            `json:"fen"`
+	StartFEN      string            `json:"start_fen,omitempty"`
+	Moves         []string          `json:"moves"`
+	MoveCount     int               `json:"move_count"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// newGameView converts a game snapshot to its REST representation
+func newGameView(g *game.GameState) GameView {
+	view := GameView{
+		GameID:    g.GameID,
+		Status:    g.Status,
+		Winner:    g.Winner,
+		Variant:   g.Variant,
+		Players:   make(map[string]string, len(g.Players)),
+		Owner:     g.Owner,
+		FEN:       g.FEN(),
+		StartFEN:  g.StartFEN,
+		Moves:     make([]string, len(g.Moves)),
+		MoveCount: g.MoveCount,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}
+	if !g.IsGameOver() {
+		view.CurrentPlayer = g.CurrentPlayer
+	}
+	for player, name := range g.Players {
+		view.Players[string(player)] = name
+	}
+	if len(g.Seats) > 0 {
+		view.Seats = make(map[string]string, len(g.Seats))
+		for player, principal := range g.Seats {
+			view.Seats[string(player)] = principal
+		}
+	}
+	for row := range g.Board {
+		for col := range g.Board[row] {
+			view.Board[row][col] = string(g.Board[row][col])
+		}
+	}
+	for i, move := range g.Moves {
+		view.Moves[i] = move.Position.String()
+	}
+	return view
+}
+
+// GameSummaryView is the REST representation of a game in a listing
+type GameSummaryView struct {
+	GameID    string            `json:"game_id"`
+	Status    game.GameStatus   `json:"status"`
+	Winner    game.Player       `json:"winner,omitempty"`
+	Variant   game.Variant      `json:"variant"`
+	Players   map[string]string `json:"players"`
+	MoveCount int               `json:"move_count"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// GameListView is a page of games returned by GET /games
+type GameListView struct {
+	Games      []GameSummaryView `json:"games"`
+	Total      int               `json:"total"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// createGameRequest is the body of POST /games
+type createGameRequest struct {
+	GameID   string `json:"game_id"`
+	PlayerX  string `json:"player_x"`
+	PlayerO  string `json:"player_o"`
+	Position string `json:"position"`
+}
+
+// moveRequest is the body of POST /games/{id}/moves
+type moveRequest struct {
+	Position string      `json:"position"`
+	Player   game.Player `json:"player"` // Defaults to the player to move
+}
+
+// RESTHandler returns the plain JSON HTTP API over the server's engine:
+//
+//	POST   /games             create a game
+//	GET    /games             list games (same filters as list_games)
+//	GET    /games/{id}        get a game
+//	DELETE /games/{id}        delete a game
+//	POST   /games/{id}/moves  play a move
+func (s *TicTacToeServer) RESTHandler() http.Handler {
+	mux := http.NewServeMux()
+	s.registerREST(mux)
+	s.registerMetrics(mux)
+	return mux
+}
+
+// registerREST adds the REST routes to a mux
+func (s *TicTacToeServer) registerREST(mux *http.ServeMux) {
+	mux.HandleFunc("POST /games", s.limitREST(s.handleRESTCreateGame))
+	mux.HandleFunc("GET /games", s.limitREST(s.handleRESTListGames))
+	mux.HandleFunc("GET /games/{id}", s.limitREST(s.handleRESTGetGame))
+	mux.HandleFunc("DELETE /games/{id}", s.limitREST(s.handleRESTDeleteGame))
+	mux.HandleFunc("POST /games/{id}/moves", s.limitREST(s.handleRESTMakeMove))
+}
+
+// handleRESTCreateGame creates a game, rejecting IDs that are in use
+func (s *TicTacToeServer) handleRESTCreateGame(w http.ResponseWriter, r *http.Request) {
+	var req createGameRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	gameID := req.GameID
+	if gameID == "" {
+		gameID = generateGameID()
+	}
+
+	var created bool
+	if s.quotas != nil {
+		client := restClientKey(r)
+		if err := s.quotas.reserve(client, gameID); err != nil {
+			writeError(w, http.StatusTooManyRequests, fmt.Sprintf("open game quota reached: %v", err))
+			return
+		}
+		defer func() { s.quotas.settle(client, gameID, created) }()
+	}
+
+	_, err := s.engine.CreateGameIfAbsent(gameID, game.GameOptions{
+		Players: map[game.Player]string{
+			game.PlayerX: req.PlayerX,
+			game.PlayerO: req.PlayerO,
+		},
+		FEN:   req.Position,
+		Owner: ownerFor(r.Context()),
+	})
+	if errors.Is(err, game.ErrGameExists) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	created = true
+
+	snapshot, err := s.engine.Snapshot(gameID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.Header().Set("Location", "/games/"+gameID)
+	writeJSON(w, http.StatusCreated, newGameView(snapshot))
+}
+
+// handleRESTListGames returns a filtered page of games
+func (s *TicTacToeServer) handleRESTListGames(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := game.GameFilter{
+		Status:  game.GameStatus(query.Get("status")),
+		Variant: game.Variant(query.Get("variant")),
+		Player:  query.Get("player"),
+		SortBy:  game.SortField(query.Get("sort")),
+		Cursor:  query.Get("cursor"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit: %v", err))
+			return
+		}
+		filter.Limit = n
+	}
+	if createdAfter := query.Get("created_after"); createdAfter != "" {
+		t, err := time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid created_after: %v", err))
+			return
+		}
+		filter.CreatedAfter = t
+	}
+	switch query.Get("order") {
+	case "asc":
+		filter.Ascending = true
+	case "", "desc":
+	default:
+		writeError(w, http.StatusBadRequest, "order must be 'asc' or 'desc'")
+		return
+	}
+
+	page, err := s.engine.QueryGames(filter)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list := GameListView{
+		Games:      make([]GameSummaryView, len(page.Games)),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	}
+	for i, summary := range page.Games {
+		players := make(map[string]string, len(summary.Players))
+		for player, name := range summary.Players {
+			players[string(player)] = name
+		}
+		list.Games[i] = GameSummaryView{
+			GameID:    summary.GameID,
+			Status:    summary.Status,
+			Winner:    summary.Winner,
+			Variant:   summary.Variant,
+			Players:   players,
+			MoveCount: summary.MoveCount,
+			CreatedAt: summary.CreatedAt,
+			UpdatedAt: summary.UpdatedAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleRESTGetGame returns a single game
+func (s *TicTacToeServer) handleRESTGetGame(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := s.engine.Snapshot(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newGameView(snapshot))
+}
+
+// handleRESTDeleteGame removes a game
+func (s *TicTacToeServer) handleRESTDeleteGame(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkOwner(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err := s.engine.DeleteGame(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRESTMakeMove plays a move. Illegal moves are conflicts with the
+// game's current state.
+func (s *TicTacToeServer) handleRESTMakeMove(w http.ResponseWriter, r *http.Request) {
+	gameID := r.PathValue("id")
+	var req moveRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	pos, err := game.ParsePosition(strings.ToUpper(req.Position))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid position: %v", err))
+		return
+	}
+
+	current, err := s.engine.Snapshot(gameID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	player := game.Player(strings.ToUpper(string(req.Player)))
+	switch player {
+	case "":
+		player = current.CurrentPlayer
+	case game.PlayerX, game.PlayerO:
+	default:
+		writeError(w, http.StatusBadRequest, "player must be 'X' or 'O'")
+		return
+	}
+
+	_, err = s.engine.MakeMoveAs(gameID, pos, player, ownerFor(r.Context()))
+	if errors.Is(err, game.ErrSeatHeld) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	snapshot, err := s.engine.Snapshot(gameID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newGameView(snapshot))
+}
+
+// errNotJSON is returned by decodeJSON for a body that is not declared as
+// JSON. Requiring the header keeps browsers on other sites from posting
+// form or text bodies without a CORS preflight.
+var errNotJSON = errors.New("request body must be application/json")
+
+// decodeJSON reads a JSON request body into v. An empty body leaves v as is.
+func decodeJSON(r *http.Request, v any) error {
+	if r.ContentLength != 0 {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			return errNotJSON
+		}
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// writeDecodeError reports a decodeJSON failure: 415 for a body that is not
+// JSON, 400 for one that does not parse
+func writeDecodeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errNotJSON) {
+		status = http.StatusUnsupportedMediaType
+	}
+	writeError(w, status, err.Error())
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// doREST sends a request to the REST handler and decodes the JSON response
+func doREST(t *testing.T, handler http.Handler, method, path, body string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if out != nil && rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s returned invalid JSON %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestRESTGameLifecycle(t *testing.T) {
+	server := NewTicTacToeServer()
+	api := server.RESTHandler()
+
+	var view GameView
+	status := doREST(t, api, "POST", "/games", `{"game_id":"rest","player_x":"ann","player_o":"bob"}`, &view)
+	if status != http.StatusCreated || view.GameID != "rest" || view.Players["X"] != "ann" || view.CurrentPlayer != "X" {
+		t.Fatalf("Unexpected create response %d: %+v", status, view)
+	}
+
+	var errorBody map[string]string
+	if status := doREST(t, api, "POST", "/games", `{"game_id":"rest"}`, &errorBody); status != http.StatusConflict || errorBody["error"] == "" {
+		t.Errorf("Creating a duplicate game should conflict, got %d %v", status, errorBody)
+	}
+
+	for _, move := range []string{"B2", "a1", "C2", "A2", "C1"} {
+		if status := doREST(t, api, "POST", "/games/rest/moves", `{"position":"`+move+`"}`, &view); status != http.StatusOK {
+			t.Fatalf("Move %s failed with %d", move, status)
+		}
+	}
+	if status := doREST(t, api, "POST", "/games/rest/moves", `{"position":"A3","player":"X"}`, &errorBody); status != http.StatusConflict {
+		t.Errorf("Playing out of turn should conflict, got %d", status)
+	}
+	view = GameView{}
+	doREST(t, api, "POST", "/games/rest/moves", `{"position":"A3","player":"O"}`, &view)
+	if view.Status != "won" || view.Winner != "O" || view.CurrentPlayer != "" || view.Board[2][0] != "O" || len(view.Moves) != 6 {
+		t.Errorf("Unexpected final state: %+v", view)
+	}
+
+	// The REST API and MCP share one engine
+	if g, err := server.engine.GetGame("rest"); err != nil || g.MoveCount != 6 {
+		t.Errorf("Engine should see the REST moves, got %+v, %v", g, err)
+	}
+
+	view = GameView{}
+	if status := doREST(t, api, "GET", "/games/rest", "", &view); status != http.StatusOK || view.FEN != "O.X/OXX/O.. o" {
+		t.Errorf("Unexpected get response %d: %+v", status, view)
+	}
+	if status := doREST(t, api, "DELETE", "/games/rest", "", nil); status != http.StatusNoContent {
+		t.Errorf("Expected 204 from delete, got %d", status)
+	}
+	if status := doREST(t, api, "GET", "/games/rest", "", &errorBody); status != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %d", status)
+	}
+}
+
+func TestRESTValidation(t *testing.T) {
+	server := NewTicTacToeServer()
+	api := server.RESTHandler()
+	server.engine.CreateGame("valid")
+
+	cases := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"POST", "/games", `{"position":"XXX/OOO/... x"}`, http.StatusBadRequest},
+		{"POST", "/games", `{"unknown":1}`, http.StatusBadRequest},
+		{"POST", "/games/valid/moves", `{"position":"D4"}`, http.StatusBadRequest},
+		{"POST", "/games/valid/moves", `{"position":"A1","player":"Z"}`, http.StatusBadRequest},
+		{"POST", "/games/missing/moves", `{"position":"A1"}`, http.StatusNotFound},
+		{"GET", "/games?order=sideways", "", http.StatusBadRequest},
+		{"GET", "/games?limit=many", "", http.StatusBadRequest},
+		{"PUT", "/games/valid", "", http.StatusMethodNotAllowed},
+	}
+	for _, c := range cases {
+		if status := doREST(t, api, c.method, c.path, c.body, nil); status != c.status {
+			t.Errorf("%s %s %s: expected %d, got %d", c.method, c.path, c.body, c.status, status)
+		}
+	}
+}
+
+func TestRESTRequiresJSONBodies(t *testing.T) {
+	server := NewTicTacToeServer()
+	api := server.RESTHandler()
+	server.engine.CreateGame("typed")
+
+	cases := []struct {
+		contentType string
+		status      int
+	}{
+		{"", http.StatusUnsupportedMediaType},
+		{"text/plain", http.StatusUnsupportedMediaType},
+		{"application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"application/json; charset=utf-8", http.StatusOK},
+	}
+	for _, c := range cases {
+		server.engine.CreateGame("typed")
+		req := httptest.NewRequest("POST", "/games/typed/moves", bytes.NewBufferString(`{"position":"B2"}`))
+		if c.contentType != "" {
+			req.Header.Set("Content-Type", c.contentType)
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if rec.Code != c.status {
+			t.Errorf("Content-Type %q: expected %d, got %d", c.contentType, c.status, rec.Code)
+		}
+	}
+
+	// A request without a body needs no Content-Type
+	if status := doREST(t, api, "POST", "/games", "", nil); status != http.StatusCreated {
+		t.Errorf("Creating a game without a body should succeed, got %d", status)
+	}
+}
+
+func TestRESTListGames(t *testing.T) {
+	server := NewTicTacToeServer()
+	api := server.RESTHandler()
+	for _, id := range []string{"a", "b", "c"} {
+		doREST(t, api, "POST", "/games", `{"game_id":"`+id+`","player_x":"ann"}`, nil)
+	}
+	doREST(t, api, "POST", "/games", `{"game_id":"d","player_x":"bob"}`, nil)
+
+	var list GameListView
+	if status := doREST(t, api, "GET", "/games?player=ann&limit=2&order=asc", "", &list); status != http.StatusOK {
+		t.Fatalf("List failed with %d", status)
+	}
+	if list.Total != 3 || len(list.Games) != 2 || list.NextCursor == "" || list.Games[0].GameID != "a" {
+		t.Fatalf("Unexpected first page: %+v", list)
+	}
+
+	cursor := list.NextCursor
+	list = GameListView{}
+	doREST(t, api, "GET", "/games?player=ann&limit=2&order=asc&cursor="+cursor, "", &list)
+	if len(list.Games) != 1 || list.Games[0].GameID != "c" || list.NextCursor != "" {
+		t.Errorf("Unexpected second page: %+v", list)
+	}
+}
+
+func TestRESTMountedWithMCP(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableREST()
+	mux := server.httpMux("/mcp", http.NotFoundHandler())
+
+	if status := doREST(t, mux, "POST", "/games", `{"game_id":"shared"}`, nil); status != http.StatusCreated {
+		t.Errorf("REST routes should be mounted next to MCP, got %d", status)
+	}
+
+	server = NewTicTacToeServer()
+	mux = server.httpMux("/mcp", http.NotFoundHandler())
+	if status := doREST(t, mux, "POST", "/games", `{}`, nil); status != http.StatusNotFound {
+		t.Errorf("REST routes should not be mounted unless enabled, got %d", status)
+	}
+}
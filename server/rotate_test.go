@@ -0,0 +1,82 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	os.WriteFile(path, []byte("old\n"), 0o644)
+
+	file, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	// Each line would take the file past 10 bytes, so each starts a new file
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	} {
+		if got, err := os.ReadFile(name); err != nil || string(got) != want {
+			t.Errorf("Expected %s to hold %q, got %q (%v)", filepath.Base(name), want, got, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Expected at most 2 backups, got %v", err)
+	}
+	if _, err := file.Write([]byte("late\n")); err == nil {
+		t.Error("Expected writing after Close to fail")
+	}
+}
+
+func TestRotatingFileAppendsBelowLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	file, err := OpenRotatingFile(path, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer file.Close()
+	file.Write([]byte("a\n"))
+	file.Write([]byte("b\n"))
+
+	if got, _ := os.ReadFile(path); string(got) != "a\nb\n" {
+		t.Errorf("Expected both lines in one file, got %q", got)
+	}
+	if _, err := OpenRotatingFile(path, -1, 0); err == nil {
+		t.Error("Expected a negative size limit to be rejected")
+	}
+}
+
+func TestRotatingFileRecoversFromCloseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	file, err := OpenRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer file.Close()
+	file.Write([]byte("first\n"))
+
+	// Closing the file underneath makes the rotation's Close fail
+	file.file.Close()
+	if _, err := file.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Write after a failed close should go to a new file: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "second\n" {
+		t.Errorf("Expected the new file to hold the write, got %q", data)
+	}
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "first\n" {
+		t.Errorf("Expected the old file as a backup, got %q", data)
+	}
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Transport names accepted by Serve
+const (
+	TransportStdio     = "stdio"
+	TransportSSE       = "sse"
+	TransportHTTP      = "http"
+	TransportWebSocket = "ws"
+)
+
+// DefaultShutdownTimeout is how long Serve waits for open HTTP requests and
+// streams when it stops
+const DefaultShutdownTimeout = 10 * time.Second
+
+// ServeOptions selects the transports Serve runs
+type ServeOptions struct {
+	Transports      []string      // Any of stdio, sse, http and ws
+	Addr            string        // Shared by the sse, http and ws transports
+	RESTAddr        string        // Optional separate listener for the REST API
+	ShutdownTimeout time.Duration // Defaults to DefaultShutdownTimeout
+}
+
+// shutdownKey is the request context key for the channel closed when the
+// HTTP listener starts shutting down
+type shutdownKey struct{}
+
+// shuttingDown returns a channel closed when the listener serving the
+// request starts shutting down, so long-lived streams can end cleanly. It is
+// nil, and never ready, outside Serve.
+func shuttingDown(ctx context.Context) <-chan struct{} {
+	done, _ := ctx.Value(shutdownKey{}).(chan struct{})
+	return done
+}
+
+// Serve runs the selected transports against this server's engine until ctx
+// is cancelled or one of them fails, then stops them all. The HTTP-based
+// transports share one listener: SSE at /sse and /message, Streamable HTTP
+// at /mcp and WebSocket at /ws, next to the REST API and browser UI if
+// enabled. With EnableTLS, the listeners serve HTTPS (and WSS). With
+// EnableAuth, every HTTP request must authenticate. Every
+// listener is bound before any transport starts, so a busy address fails
+// the whole call. The stdio transport ends when stdin closes, which stops
+// the other transports too.
+//
+// Stopping is graceful: clients are sent ShutdownNotification, new tool
+// calls are refused, and running ones get ShutdownTimeout to finish before
+// the listeners close. Games are then saved to the state file, if enabled.
+// The server refuses tool calls once Serve has returned.
+func (s *TicTacToeServer) Serve(ctx context.Context, opts ServeOptions) error {
+	transports := make(map[string]bool)
+	for _, transport := range opts.Transports {
+		switch transport {
+		case TransportStdio, TransportSSE, TransportHTTP, TransportWebSocket:
+		default:
+			return fmt.Errorf("unknown transport: %s (supported: stdio, sse, http, ws)", transport)
+		}
+		if transports[transport] {
+			return fmt.Errorf("transport %s given more than once", transport)
+		}
+		transports[transport] = true
+	}
+	if len(transports) == 0 && opts.RESTAddr == "" {
+		return errors.New("no transports to serve")
+	}
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	var listeners []*httpListener
+	closeAll := func() {
+		for _, l := range listeners {
+			l.listener.Close()
+		}
+	}
+
+	if transports[TransportSSE] || transports[TransportHTTP] || transports[TransportWebSocket] {
+		mux := s.baseMux()
+		l, err := s.newHTTPListener(opts.Addr, s.authenticate(mux))
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, l)
+
+		if transports[TransportSSE] {
+			// The SSE server closes its sessions when shut down, which it
+			// can only do if it owns the HTTP server
+			sseServer := server.NewSSEServer(s.mcpServer, server.WithHTTPServer(l.server))
+			mux.Handle(sseServer.CompleteSsePath(), tagTransport(TransportSSE, sseServer))
+			mux.Handle(sseServer.CompleteMessagePath(), tagTransport(TransportSSE, sseServer))
+			l.shutdown = sseServer.Shutdown
+			l.names = append(l.names, "SSE transport")
+		}
+		if transports[TransportHTTP] {
+			mux.Handle("/mcp", tagTransport(TransportHTTP, server.NewStreamableHTTPServer(s.mcpServer)))
+			l.names = append(l.names, "Streamable HTTP transport")
+		}
+		if transports[TransportWebSocket] {
+			mux.Handle("/ws", s.WebSocketHandler())
+			l.names = append(l.names, "WebSocket transport")
+		}
+	}
+	if opts.RESTAddr != "" {
+		l, err := s.newHTTPListener(opts.RESTAddr, s.authenticate(s.RESTHandler()))
+		if err != nil {
+			closeAll()
+			return err
+		}
+		l.names = append(l.names, "REST API")
+		listeners = append(listeners, l)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Stdio keeps running while tool calls drain, so it can still answer
+	stdioCtx, stopStdio := context.WithCancel(context.Background())
+	defer stopStdio()
+
+	// The first transport to stop, for whatever reason, stops the rest
+	var (
+		wg       sync.WaitGroup
+		errMutex sync.Mutex
+		firstErr error
+	)
+	stop := func(err error) {
+		errMutex.Lock()
+		if firstErr == nil && err != nil {
+			firstErr = err
+		}
+		errMutex.Unlock()
+		cancel()
+	}
+
+	for _, l := range listeners {
+		scheme := "http"
+		if s.tls != nil {
+			scheme = "https"
+		}
+		slog.Info("Serving", "serves", joinNames(l.names), "url", fmt.Sprintf("%s://%s", scheme, l.listener.Addr()))
+		wg.Add(1)
+		go func(l *httpListener) {
+			defer wg.Done()
+			if err := l.server.Serve(l.listener); !errors.Is(err, http.ErrServerClosed) {
+				stop(fmt.Errorf("%s: %w", joinNames(l.names), err))
+			}
+		}(l)
+	}
+
+	if transports[TransportStdio] {
+		slog.Info("Serving", "serves", "stdio transport")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stdio := server.NewStdioServer(s.mcpServer)
+			stdio.SetErrorLogger(slog.NewLogLogger(slog.Default().Handler(), slog.LevelError))
+			err := stdio.Listen(withTransport(stdioCtx, TransportStdio), os.Stdin, os.Stdout)
+			if errors.Is(err, context.Canceled) {
+				err = nil
+			}
+			stop(err)
+		}()
+	}
+
+	<-ctx.Done()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancelShutdown()
+	s.drain(shutdownCtx)
+	stopStdio()
+	for _, l := range listeners {
+		wg.Add(1)
+		go func(l *httpListener) {
+			defer wg.Done()
+			l.stop(shutdownCtx)
+		}(l)
+	}
+	wg.Wait()
+
+	if err := s.saveState(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// ServeStdio starts the server using stdio transport
+func (s *TicTacToeServer) ServeStdio() error {
+	return s.Serve(context.Background(), ServeOptions{Transports: []string{TransportStdio}})
+}
+
+// ServeSSE starts the server using Server-Sent Events transport
+func (s *TicTacToeServer) ServeSSE(addr string) error {
+	return s.Serve(context.Background(), ServeOptions{Transports: []string{TransportSSE}, Addr: addr})
+}
+
+// ServeStreamableHTTP starts the server using Streamable HTTP transport
+func (s *TicTacToeServer) ServeStreamableHTTP(addr string) error {
+	return s.Serve(context.Background(), ServeOptions{Transports: []string{TransportHTTP}, Addr: addr})
+}
+
+// ServeWebSocket starts the server using the WebSocket transport at /ws
+func (s *TicTacToeServer) ServeWebSocket(addr string) error {
+	return s.Serve(context.Background(), ServeOptions{Transports: []string{TransportWebSocket}, Addr: addr})
+}
+
+// ServeREST starts the REST API on its own address, e.g. alongside stdio
+func (s *TicTacToeServer) ServeREST(addr string) error {
+	return s.Serve(context.Background(), ServeOptions{RESTAddr: addr})
+}
+
+// httpListener is a bound address and the HTTP server that will serve it
+type httpListener struct {
+	listener net.Listener
+	server   *http.Server
+	names    []string                        // What is served, for logs and errors
+	shutdown func(ctx context.Context) error // Defaults to server.Shutdown
+	closing  chan struct{}                   // Closed when shutdown starts
+}
+
+// newHTTPListener binds addr for handler, over TLS if enabled. Requests
+// carry a channel that is closed when the listener shuts down; see
+// shuttingDown.
+func (s *TicTacToeServer) newHTTPListener(addr string, handler http.Handler) (*httpListener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s failed: %w", addr, err)
+	}
+	if s.tls != nil {
+		listener = tls.NewListener(listener, s.tls.config())
+	}
+
+	l := &httpListener{
+		listener: listener,
+		server:   &http.Server{Handler: handler},
+		closing:  make(chan struct{}),
+	}
+	l.server.BaseContext = func(net.Listener) context.Context {
+		return context.WithValue(context.Background(), shutdownKey{}, l.closing)
+	}
+	l.server.RegisterOnShutdown(func() { close(l.closing) })
+	l.shutdown = l.server.Shutdown
+	return l, nil
+}
+
+// stop shuts the listener down gracefully, closing any connections still
+// open when ctx expires
+func (l *httpListener) stop(ctx context.Context) {
+	if err := l.shutdown(ctx); err != nil {
+		slog.Warn("Listener did not shut down cleanly", "serves", joinNames(l.names), "error", err)
+		l.server.Close()
+	}
+}
+
+// joinNames lists what a listener serves, e.g. "SSE transport and REST API"
+func joinNames(names []string) string {
+	switch len(names) {
+	case 0:
+		return "MCP server"
+	case 1:
+		return names[0]
+	}
+	joined := names[0]
+	for _, name := range names[1 : len(names)-1] {
+		joined += ", " + name
+	}
+	return joined + " and " + names[len(names)-1]
+}
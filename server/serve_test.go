@@ -0,0 +1,215 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// freeAddr returns a loopback address nothing is listening on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// startServe runs Serve in the background until its address accepts
+// connections. The returned channel yields Serve's result.
+func startServe(t *testing.T, ctx context.Context, server *TicTacToeServer, opts ServeOptions) <-chan error {
+	t.Helper()
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(ctx, opts) }()
+	waitListening(t, opts.Addr)
+	return result
+}
+
+// waitListening waits for addr to accept connections
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Server did not start: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// postMCP sends one JSON-RPC message to the Streamable HTTP transport
+func postMCP(t *testing.T, url, sessionID string, message map[string]any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(message)
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", url, err)
+	}
+	return resp
+}
+
+func TestServeSharesEngineAcrossTransports(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableREST()
+	addr := freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := startServe(t, ctx, server, ServeOptions{
+		Transports: []string{TransportSSE, TransportHTTP, TransportWebSocket},
+		Addr:       addr,
+	})
+
+	// Create a game over WebSocket
+	ws := dialWebSocket(t, "http://"+addr)
+	defer ws.conn.Close()
+	initializeWebSocket(ws)
+	ws.callTool("new_game", map[string]any{"game_id": "shared"})
+
+	// Play in it over Streamable HTTP
+	resp := postMCP(t, "http://"+addr+"/mcp", "", map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2025-03-26",
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0"},
+		},
+	})
+	resp.Body.Close()
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	resp = postMCP(t, "http://"+addr+"/mcp", sessionID, map[string]any{
+		"jsonrpc": "2.0", "id": 2, "method": "tools/call",
+		"params": map[string]any{
+			"name":      "make_move",
+			"arguments": map[string]any{"game_id": "shared", "position": "A1", "player": "X"},
+		},
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Streamable HTTP move returned %d", resp.StatusCode)
+	}
+
+	// The SSE endpoint is up on the same address
+	sseResp, err := http.Get("http://" + addr + "/sse")
+	if err != nil {
+		t.Fatalf("GET /sse failed: %v", err)
+	}
+	if !strings.HasPrefix(sseResp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Errorf("Expected an event stream from /sse, got %q", sseResp.Header.Get("Content-Type"))
+	}
+	defer sseResp.Body.Close()
+
+	// And the REST API sees the move
+	restResp, err := http.Get("http://" + addr + "/games/shared")
+	if err != nil {
+		t.Fatalf("GET /games/shared failed: %v", err)
+	}
+	var view GameView
+	json.NewDecoder(restResp.Body).Decode(&view)
+	restResp.Body.Close()
+	if len(view.Moves) != 1 || view.Moves[0] != "A1" {
+		t.Errorf("Expected the move played over HTTP, got %v", view.Moves)
+	}
+
+	// Cancelling stops every transport: clients are told, then open
+	// streams are closed
+	cancel()
+	ws.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var notification map[string]any
+	if err := ws.conn.ReadJSON(&notification); err != nil || notification["method"] != ShutdownNotification {
+		t.Errorf("Expected the shutdown notification, got %v, %v", notification, err)
+	}
+	_, _, err = ws.conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("Expected the WebSocket to be closed as going away, got %v", err)
+	}
+	ws.conn.Close()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("Serve returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after cancellation")
+	}
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Error("Expected the listener to be closed")
+	}
+}
+
+func TestServeSeparateRESTListener(t *testing.T) {
+	server := NewTicTacToeServer()
+	mcpAddr, restAddr := freeAddr(t), freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := startServe(t, ctx, server, ServeOptions{
+		Transports: []string{TransportHTTP},
+		Addr:       mcpAddr,
+		RESTAddr:   restAddr,
+	})
+	waitListening(t, restAddr)
+
+	resp, err := http.Get("http://" + restAddr + "/games")
+	if err != nil {
+		t.Fatalf("GET /games failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the REST API on its own address, got %d", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-result; err != nil {
+		t.Errorf("Serve returned %v", err)
+	}
+}
+
+func TestServeRejectsBadOptions(t *testing.T) {
+	server := NewTicTacToeServer()
+
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer busy.Close()
+
+	tests := []struct {
+		name string
+		opts ServeOptions
+		want string
+	}{
+		{"no transports", ServeOptions{}, "no transports"},
+		{"unknown transport", ServeOptions{Transports: []string{"carrier-pigeon"}}, "unknown transport"},
+		{"repeated transport", ServeOptions{Transports: []string{"http", "http"}}, "more than once"},
+		{"busy address", ServeOptions{Transports: []string{"http"}, Addr: busy.Addr().String()}, "listening on"},
+		{"busy REST address", ServeOptions{Transports: []string{"http"}, Addr: "127.0.0.1:0", RESTAddr: busy.Addr().String()}, "listening on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := server.Serve(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
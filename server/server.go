@@ -3,7 +3,7 @@ package server
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"log"
+	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -12,27 +12,57 @@ import (
 
 // TicTacToeServer wraps the game engine with MCP server functionality
 type TicTacToeServer struct {
-	mcpServer *server.MCPServer
-	engine    *game.Engine
+	mcpServer    *server.MCPServer
+	engine       *game.Engine
+	spectators   *spectators
+	calls        toolCalls
+	metrics      *serverMetrics    // Always collected; served only if serveMetrics
+	audit        *auditLog         // Records every tool call, if set
+	auth         Authenticator     // Required on the HTTP listeners, if set
+	tls          *certReloader     // Serves the HTTP listeners over TLS, if set
+	rateLimiter  *rateLimiter      // Limits calls per client, if set
+	quotas       *gameQuotas       // Limits open games per client, if set
+	sessions     sessionPrincipals // Principal that opened each MCP session
+	rest         bool              // Serve the REST API next to the MCP HTTP transports
+	ui           bool              // Serve the browser UI next to the MCP HTTP transports
+	stateFile    string            // Where games are saved when Serve stops, if set
+	serveMetrics bool              // Serve Prometheus metrics at /metrics on the HTTP listeners
 }
 
 // NewTicTacToeServer creates a new MCP server for tic-tac-toe
 func NewTicTacToeServer() *TicTacToeServer {
+	engine := game.NewEngine()
 	s := &TicTacToeServer{
-		engine: game.NewEngine(),
+		engine:     engine,
+		spectators: newSpectators(),
+		metrics:    newServerMetrics(engine),
 	}
 
+	hooks := &server.Hooks{}
+	s.addSpectatorHooks(hooks)
+	s.addAuthHooks(hooks)
+	s.addMetricsHooks(hooks)
+
 	// Create MCP server with tool capabilities
 	s.mcpServer = server.NewMCPServer(
 		"Tic-Tac-Toe Game Server",
 		"1.0.0",
 		server.WithToolCapabilities(true),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(s.measureToolCalls),
+		server.WithToolHandlerMiddleware(s.auditToolCalls),
+		server.WithToolHandlerMiddleware(s.trackToolCalls),
+		server.WithToolHandlerMiddleware(s.checkSessionPrincipal),
+		server.WithToolHandlerMiddleware(s.limitToolCalls),
 	)
 
 	// Register all tools
 	s.registerTools()
 
+	// Stream game events to spectators
+	s.engine.Events().Subscribe(s.notifySpectators)
+
 	return s
 }
 
@@ -44,6 +74,15 @@ func (s *TicTacToeServer) registerTools() {
 		mcp.WithString("game_id",
 			mcp.Description("Optional game ID. If not provided, a random ID will be generated"),
 		),
+		mcp.WithString("player_x",
+			mcp.Description("Optional name of the participant playing X"),
+		),
+		mcp.WithString("player_o",
+			mcp.Description("Optional name of the participant playing O"),
+		),
+		mcp.WithString("position",
+			mcp.Description("Optional starting position in compact notation: rows 1-3 separated by '/', X/O/. per cell, then the side to move (e.g. 'X.O/.X./..O x')"),
+		),
 	)
 	s.mcpServer.AddTool(newGameTool, s.handleNewGame)
 
@@ -119,9 +158,149 @@ func (s *TicTacToeServer) registerTools() {
 
 	// List games tool
 	listGamesTool := mcp.NewTool("list_games",
-		mcp.WithDescription("List all active game IDs"),
+		mcp.WithDescription("List games with optional filters, sorting and cursor-based pagination"),
+		mcp.WithString("status",
+			mcp.Description("Only include games with this status"),
+			mcp.Enum("ongoing", "won", "draw"),
+		),
+		mcp.WithString("variant",
+			mcp.Description("Only include games of this variant"),
+		),
+		mcp.WithString("player",
+			mcp.Description("Only include games where this participant plays either side"),
+		),
+		mcp.WithString("created_after",
+			mcp.Description("Only include games created after this RFC 3339 timestamp"),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Timestamp to sort by (default: created)"),
+			mcp.Enum("created", "updated"),
+		),
+		mcp.WithString("order",
+			mcp.Description("Sort order (default: desc, newest first)"),
+			mcp.Enum("asc", "desc"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of games to return (default 20, max 100)"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Cursor returned by a previous list_games call to fetch the next page"),
+		),
 	)
 	s.mcpServer.AddTool(listGamesTool, s.handleListGames)
+
+	// Export game tool
+	exportGameTool := mcp.NewTool("export_game",
+		mcp.WithDescription("Export a game as a PGN-like text record with tag pairs and an A1-C3 move list"),
+		mcp.WithString("game_id",
+			mcp.Required(),
+			mcp.Description("ID of the game to export"),
+		),
+	)
+	s.mcpServer.AddTool(exportGameTool, s.handleExportGame)
+
+	// Import game tool
+	importGameTool := mcp.NewTool("import_game",
+		mcp.WithDescription("Import a game record produced by export_game, replaying and validating its moves"),
+		mcp.WithString("record",
+			mcp.Required(),
+			mcp.Description("Game record text"),
+		),
+		mcp.WithString("game_id",
+			mcp.Description("Optional ID for the imported game. Defaults to the record's Game tag"),
+		),
+	)
+	s.mcpServer.AddTool(importGameTool, s.handleImportGame)
+
+	// Get puzzle tool
+	getPuzzleTool := mcp.NewTool("get_puzzle",
+		mcp.WithDescription("Start a tactical puzzle game: win in N, find the only drawing move, or block a fork"),
+		mcp.WithString("kind",
+			mcp.Description("Optional puzzle theme. If not provided, any theme may be chosen"),
+			mcp.Enum("win-in-n", "only-draw", "block-fork"),
+		),
+		mcp.WithNumber("difficulty",
+			mcp.Description("Optional difficulty from 1 (easiest) to 5"),
+			mcp.Min(1),
+			mcp.Max(5),
+		),
+		mcp.WithString("puzzle_id",
+			mcp.Description("Optional ID of a specific puzzle to play"),
+		),
+		mcp.WithString("game_id",
+			mcp.Description("Optional game ID. If not provided, a random ID will be generated"),
+		),
+	)
+	s.mcpServer.AddTool(getPuzzleTool, s.handleGetPuzzle)
+
+	// Check puzzle answer tool
+	checkPuzzleAnswerTool := mcp.NewTool("check_puzzle_answer",
+		mcp.WithDescription("Check a proposed move for a puzzle game. Correct answers are played on the board"),
+		mcp.WithString("game_id",
+			mcp.Required(),
+			mcp.Description("ID of the puzzle game"),
+		),
+		mcp.WithString("position",
+			mcp.Required(),
+			mcp.Description("Proposed move (A1-C3 format)"),
+			mcp.Enum("A1", "A2", "A3", "B1", "B2", "B3", "C1", "C2", "C3"),
+		),
+	)
+	s.mcpServer.AddTool(checkPuzzleAnswerTool, s.handleCheckPuzzleAnswer)
+
+	// Get hint tool
+	getHintTool := mcp.NewTool("get_hint",
+		mcp.WithDescription("Get a hint for the player to move. Each hint costs its level in points from the game's hint budget"),
+		mcp.WithString("game_id",
+			mcp.Required(),
+			mcp.Description("ID of the game to get a hint for"),
+		),
+		mcp.WithNumber("level",
+			mcp.Description("1: thematic nudge, 2: threat squares, 3: best move (default 1)"),
+			mcp.Min(1),
+			mcp.Max(3),
+		),
+	)
+	s.mcpServer.AddTool(getHintTool, s.handleGetHint)
+
+	// Review game tool
+	reviewGameTool := mcp.NewTool("review_game",
+		mcp.WithDescription("Review a finished game: classify each move as best, inaccuracy or blunder and find the decisive mistake"),
+		mcp.WithString("game_id",
+			mcp.Required(),
+			mcp.Description("ID of the finished game to review"),
+		),
+	)
+	s.mcpServer.AddTool(reviewGameTool, s.handleReviewGame)
+
+	// Opening explorer tool
+	openingExplorerTool := mcp.NewTool("opening_explorer",
+		mcp.WithDescription("Show how finished games that began with a move sequence continued, with game counts and result percentages per next move"),
+		mcp.WithString("moves",
+			mcp.Description("Opening moves from the empty board, e.g. 'B2 A1' or '1. B2 A1' (default: start position)"),
+		),
+	)
+	s.mcpServer.AddTool(openingExplorerTool, s.handleOpeningExplorer)
+
+	// Watch game tool
+	watchGameTool := mcp.NewTool("watch_game",
+		mcp.WithDescription("Follow a game as a read-only spectator. Every change to the game is sent to this session as a "+GameEventNotification+" notification"),
+		mcp.WithString("game_id",
+			mcp.Required(),
+			mcp.Description("ID of the game to watch"),
+		),
+	)
+	s.mcpServer.AddTool(watchGameTool, s.handleWatchGame)
+
+	// Unwatch game tool
+	unwatchGameTool := mcp.NewTool("unwatch_game",
+		mcp.WithDescription("Stop receiving notifications for a watched game"),
+		mcp.WithString("game_id",
+			mcp.Required(),
+			mcp.Description("ID of the game to stop watching"),
+		),
+	)
+	s.mcpServer.AddTool(unwatchGameTool, s.handleUnwatchGame)
 }
 
 // generateGameID creates a random game ID
@@ -136,22 +315,35 @@ func (s *TicTacToeServer) GetMCPServer() *server.MCPServer {
 	return s.mcpServer
 }
 
-// ServeStdio starts the server using stdio transport
-func (s *TicTacToeServer) ServeStdio() error {
-	log.Println("Starting Tic-Tac-Toe MCP server with stdio transport...")
-	return server.ServeStdio(s.mcpServer)
+// EnableREST serves the REST API on the same address as the HTTP-based
+// transports (SSE, Streamable HTTP and WebSocket)
+func (s *TicTacToeServer) EnableREST() {
+	s.rest = true
 }
 
-// ServeSSE starts the server using Server-Sent Events transport
-func (s *TicTacToeServer) ServeSSE(addr string) error {
-	log.Printf("Starting Tic-Tac-Toe MCP server with SSE transport on %s...", addr)
-	sseServer := server.NewSSEServer(s.mcpServer)
-	return sseServer.Start(addr)
+// EnableUI serves the browser UI at /ui/ on the same address as the
+// HTTP-based transports. The UI uses the REST API, so it is served too.
+func (s *TicTacToeServer) EnableUI() {
+	s.ui = true
+}
+
+// baseMux returns a mux with the REST API and browser UI, if enabled
+func (s *TicTacToeServer) baseMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	if s.rest || s.ui {
+		s.registerREST(mux)
+	}
+	if s.ui {
+		s.registerUI(mux)
+	}
+	s.registerMetrics(mux)
+	return mux
 }
 
-// ServeStreamableHTTP starts the server using Streamable HTTP transport
-func (s *TicTacToeServer) ServeStreamableHTTP(addr string) error {
-	log.Printf("Starting Tic-Tac-Toe MCP server with Streamable HTTP transport on %s...", addr)
-	httpServer := server.NewStreamableHTTPServer(s.mcpServer)
-	return httpServer.Start(addr)
+// httpMux routes pattern to the MCP transport and, if enabled, the REST API
+// and browser UI to their own paths
+func (s *TicTacToeServer) httpMux(pattern string, transport http.Handler) *http.ServeMux {
+	mux := s.baseMux()
+	mux.Handle(pattern, transport)
+	return mux
 }
@@ -2,10 +2,13 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"mcp-tic-tac-toe/game"
 )
 
 func TestNewTicTacToeServer(t *testing.T) {
@@ -164,6 +167,53 @@ func TestGetBoardTool(t *testing.T) {
 	}
 }
 
+// TestBoardToolsDuringPlay reads games while another goroutine plays them;
+// run with -race to check that handlers format copies, not live games
+func TestBoardToolsDuringPlay(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+	server.engine.CreateGame("busy")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			server.engine.ResetGame("busy")
+			for j, pos := range []string{"B2", "A1", "C2", "A2", "C1"} {
+				player := "X"
+				if j%2 == 1 {
+					player = "O"
+				}
+				move := mcp.CallToolRequest{Params: mcp.CallToolParams{
+					Name:      "make_move",
+					Arguments: map[string]interface{}{"game_id": "busy", "position": pos, "player": player},
+				}}
+				if result, _ := server.handleMakeMove(ctx, move); result.IsError {
+					t.Errorf("make_move failed: %s", getTextFromResult(result))
+					return
+				}
+			}
+		}
+	}()
+
+	read := mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Arguments: map[string]interface{}{"game_id": "busy"},
+	}}
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if result, err := server.handleGetBoard(ctx, read); err != nil || result.IsError {
+			t.Fatalf("get_board failed: %v %s", err, getTextFromResult(result))
+		}
+		if result, err := server.handleGetStatus(ctx, read); err != nil || result.IsError {
+			t.Fatalf("get_game_status failed: %v %s", err, getTextFromResult(result))
+		}
+	}
+}
+
 func TestListGamesTool(t *testing.T) {
 	server := NewTicTacToeServer()
 	ctx := context.Background()
@@ -202,6 +252,267 @@ func TestListGamesTool(t *testing.T) {
 	if !strings.Contains(response, "game1") || !strings.Contains(response, "game2") {
 		t.Error("Response should contain both game IDs")
 	}
+
+	// Paginate with a limit of one
+	request.Params.Arguments = map[string]interface{}{
+		"limit": float64(1),
+	}
+	result, err = server.handleListGames(ctx, request)
+	if err != nil {
+		t.Fatalf("handleListGames failed with limit: %v", err)
+	}
+
+	response = getTextFromResult(result)
+	if !strings.Contains(response, "Next cursor:") {
+		t.Error("Response should include a cursor for the next page")
+	}
+
+	// Filter by status
+	request.Params.Arguments = map[string]interface{}{
+		"status": "draw",
+	}
+	result, _ = server.handleListGames(ctx, request)
+	if !strings.Contains(getTextFromResult(result), "No active games") {
+		t.Error("Response should show no games for the draw filter")
+	}
+}
+
+func TestExportImportTools(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+
+	server.engine.CreateGame("original")
+	for i, pos := range []string{"B2", "A1"} {
+		player := "X"
+		if i == 1 {
+			player = "O"
+		}
+		server.handleMakeMove(ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "make_move",
+				Arguments: map[string]interface{}{"game_id": "original", "position": pos, "player": player},
+			},
+		})
+	}
+
+	result, err := server.handleExportGame(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "export_game",
+			Arguments: map[string]interface{}{"game_id": "original"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleExportGame failed: %v", getTextFromResult(result))
+	}
+	record := getTextFromResult(result)
+	if !strings.Contains(record, "1. B2 A1 *") {
+		t.Errorf("Record should contain the move list, got:\n%s", record)
+	}
+
+	result, err = server.handleImportGame(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "import_game",
+			Arguments: map[string]interface{}{"record": record, "game_id": "copy"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleImportGame failed: %v", getTextFromResult(result))
+	}
+	if !strings.Contains(getTextFromResult(result), "Next player: X") {
+		t.Error("Imported game should continue with X to move")
+	}
+
+	// Importing under the original ID must not clobber it
+	result, _ = server.handleImportGame(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "import_game",
+			Arguments: map[string]interface{}{"record": record},
+		},
+	})
+	if !result.IsError {
+		t.Error("Import should fail when the game ID already exists")
+	}
+}
+
+func TestPuzzleTools(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+
+	result, err := server.handleGetPuzzle(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_puzzle",
+			Arguments: map[string]interface{}{"kind": "win-in-n", "difficulty": float64(1), "game_id": "puzzle"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleGetPuzzle failed: %v", getTextFromResult(result))
+	}
+	if !strings.Contains(getTextFromResult(result), "to play and win in 1") {
+		t.Errorf("Response should contain the puzzle prompt, got:\n%s", getTextFromResult(result))
+	}
+
+	gameState, _ := server.engine.GetGame("puzzle")
+	answer := gameState.Puzzle.Solutions[0].String()
+
+	result, err = server.handleCheckPuzzleAnswer(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "check_puzzle_answer",
+			Arguments: map[string]interface{}{"game_id": "puzzle", "position": answer},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleCheckPuzzleAnswer failed: %v", getTextFromResult(result))
+	}
+	response := getTextFromResult(result)
+	if !strings.Contains(response, "Correct!") || !strings.Contains(response, "wins!") {
+		t.Errorf("Winning answer should solve the puzzle and end the game, got:\n%s", response)
+	}
+
+	result, _ = server.handleGetPuzzle(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_puzzle",
+			Arguments: map[string]interface{}{"puzzle_id": "no-such-puzzle"},
+		},
+	})
+	if !result.IsError {
+		t.Error("Unknown puzzle ID should return an error")
+	}
+}
+
+func TestGetHintTool(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+
+	server.engine.CreateGame("hint-game")
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_hint",
+			Arguments: map[string]interface{}{"game_id": "hint-game", "level": float64(3)},
+		},
+	}
+
+	result, err := server.handleGetHint(ctx, request)
+	if err != nil || result.IsError {
+		t.Fatalf("handleGetHint failed: %v", getTextFromResult(result))
+	}
+	response := getTextFromResult(result)
+	if !strings.Contains(response, "Hint (level 3): Play") || !strings.Contains(response, "remaining: 3") {
+		t.Errorf("Unexpected hint response:\n%s", response)
+	}
+
+	// Three points left cannot buy two more level 3 hints
+	server.handleGetHint(ctx, request)
+	result, _ = server.handleGetHint(ctx, request)
+	if !result.IsError {
+		t.Error("Hint should be refused once the budget is exhausted")
+	}
+}
+
+func TestAnalyzePositionTool(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+
+	server.engine.CreateGameWithOptions("analysis", game.GameOptions{FEN: "XX./O../... o"})
+
+	result, err := server.handleAnalyzePosition(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "analyze_position",
+			Arguments: map[string]interface{}{"game_id": "analysis"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleAnalyzePosition failed: %v", getTextFromResult(result))
+	}
+	if !strings.Contains(getTextFromResult(result), "O must block: C1") {
+		t.Errorf("Analysis should name the forced block, got:\n%s", getTextFromResult(result))
+	}
+
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected text and JSON content, got %d items", len(result.Content))
+	}
+	var structured map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &structured); err != nil {
+		t.Fatalf("Second content item should be JSON: %v", err)
+	}
+	if blocks, _ := structured["forced_blocks"].([]interface{}); len(blocks) != 1 || blocks[0] != "C1" {
+		t.Errorf("Expected forced_blocks [C1], got %v", structured["forced_blocks"])
+	}
+}
+
+func TestReviewGameTool(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+
+	record, _ := game.ParseRecord("1. B2 B1 2. A1 C3 3. A3 C1 4. A2 1-0")
+	server.engine.ImportGame("reviewed", record)
+
+	result, err := server.handleReviewGame(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "review_game",
+			Arguments: map[string]interface{}{"game_id": "reviewed"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleReviewGame failed: %v", getTextFromResult(result))
+	}
+	response := getTextFromResult(result)
+	if !strings.Contains(response, "2. O B1 - blunder") || !strings.Contains(response, "Decisive mistake: move 2") {
+		t.Errorf("Unexpected review:\n%s", response)
+	}
+
+	server.engine.CreateGame("unfinished")
+	result, _ = server.handleReviewGame(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "review_game",
+			Arguments: map[string]interface{}{"game_id": "unfinished"},
+		},
+	})
+	if !result.IsError {
+		t.Error("Reviewing an unfinished game should fail")
+	}
+}
+
+func TestOpeningExplorerTool(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+
+	for i, text := range []string{
+		"1. B2 B1 2. A1 C3 3. A3 C1 4. A2 1-0",
+		"1. B2 A1 2. C2 A2 3. C1 A3 0-1",
+		"1. A1 B2 2. C3 B1 3. B3 *",
+	} {
+		record, _ := game.ParseRecord(text)
+		if _, err := server.engine.ImportGame(fmt.Sprintf("g%d", i), record); err != nil {
+			t.Fatalf("ImportGame failed: %v", err)
+		}
+	}
+
+	result, err := server.handleOpeningExplorer(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "opening_explorer",
+			Arguments: map[string]interface{}{"moves": "B2"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("handleOpeningExplorer failed: %v", getTextFromResult(result))
+	}
+	response := getTextFromResult(result)
+	for _, want := range []string{"Games: 2 | X wins 50.0%", "- A1: 1 games (50.0%)", "- B1: 1 games", "theory: loss in"} {
+		if !strings.Contains(response, want) {
+			t.Errorf("Expected %q in:\n%s", want, response)
+		}
+	}
+
+	result, _ = server.handleOpeningExplorer(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "opening_explorer",
+			Arguments: map[string]interface{}{"moves": "B2, B2"},
+		},
+	})
+	if !result.IsError {
+		t.Error("Replaying an occupied square should fail")
+	}
 }
 
 // Helper functions
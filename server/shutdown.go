@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ShutdownNotification is the MCP notification method every connected
+// client receives when the server starts shutting down
+const ShutdownNotification = "notifications/tictactoe/server_shutdown"
+
+// toolCalls counts running tool calls so shutdown can wait for them
+type toolCalls struct {
+	mutex    sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{} // Closed once draining with no calls running
+}
+
+// begin records a call starting, reporting false once draining has begun
+func (c *toolCalls) begin() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.draining {
+		return false
+	}
+	c.active++
+	return true
+}
+
+// end records a call finishing
+func (c *toolCalls) end() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.active--
+	if c.draining && c.active == 0 {
+		close(c.idle)
+	}
+}
+
+// drain stops new calls from starting and waits for running ones until ctx
+// is done
+func (c *toolCalls) drain(ctx context.Context) error {
+	c.mutex.Lock()
+	if !c.draining {
+		c.draining = true
+		c.idle = make(chan struct{})
+		if c.active == 0 {
+			close(c.idle)
+		}
+	}
+	idle := c.idle
+	c.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		return fmt.Errorf("%d tool call(s) still running", c.active)
+	}
+}
+
+// trackToolCalls is tool handler middleware that counts running calls and
+// turns new ones away once shutdown has begun
+func (s *TicTacToeServer) trackToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !s.calls.begin() {
+			return mcp.NewToolResultError("Server is shutting down; retry once it is back"), nil
+		}
+		defer s.calls.end()
+		return next(ctx, request)
+	}
+}
+
+// EnableStateFile restores the games saved in path, if it exists, and
+// saves every game there when Serve stops. It returns how many games were
+// restored.
+func (s *TicTacToeServer) EnableStateFile(path string) (int, error) {
+	n, err := s.engine.LoadState(path)
+	if err != nil {
+		return 0, err
+	}
+	s.stateFile = path
+	return n, nil
+}
+
+// drain tells connected clients the server is going away, then waits for
+// running tool calls until ctx is done. New tool calls fail from here on.
+func (s *TicTacToeServer) drain(ctx context.Context) {
+	params := map[string]any{"message": "Server is shutting down"}
+	if deadline, ok := ctx.Deadline(); ok {
+		params["grace_period_ms"] = time.Until(deadline).Milliseconds()
+	}
+	s.mcpServer.SendNotificationToAllClients(ShutdownNotification, params)
+
+	if err := s.calls.drain(ctx); err != nil {
+		slog.Warn("Stopping with tool calls still running", "error", err)
+	}
+}
+
+// saveState writes every game to the state file, if one is enabled
+func (s *TicTacToeServer) saveState() error {
+	if s.stateFile == "" {
+		return nil
+	}
+	if err := s.engine.SaveState(s.stateFile); err != nil {
+		return err
+	}
+	slog.Info("Saved games", "count", len(s.engine.ListGames()), "path", s.stateFile)
+	return nil
+}
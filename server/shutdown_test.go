@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"mcp-tic-tac-toe/game"
+)
+
+func TestToolCallsDrain(t *testing.T) {
+	var calls toolCalls
+	if !calls.begin() {
+		t.Fatal("Expected a call to start before draining")
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- calls.drain(context.Background()) }()
+
+	// Once draining starts, new calls are refused
+	deadline := time.Now().Add(time.Second)
+	for calls.begin() {
+		calls.end()
+		if time.Now().After(deadline) {
+			t.Fatal("Expected new calls to be refused while draining")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned with a call running: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	calls.end()
+	if err := <-drained; err != nil {
+		t.Errorf("Expected drain to finish cleanly, got %v", err)
+	}
+}
+
+func TestToolCallsDrainTimeout(t *testing.T) {
+	var calls toolCalls
+	calls.begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := calls.drain(ctx)
+	if err == nil || !strings.Contains(err.Error(), "1 tool call(s) still running") {
+		t.Errorf("Expected drain to time out, got %v", err)
+	}
+}
+
+func TestToolCallsRefusedAfterShutdown(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.drain(context.Background())
+
+	handler := server.trackToolCalls(server.handleNewGame)
+	result, err := handler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "new_game", Arguments: map[string]interface{}{}},
+	})
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "shutting down") {
+		t.Errorf("Expected the call to be refused, got %q", getTextFromResult(result))
+	}
+	if len(server.engine.ListGames()) != 0 {
+		t.Error("Expected no game to be created during shutdown")
+	}
+}
+
+func TestServeSavesStateOnShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.json")
+
+	server := NewTicTacToeServer()
+	if n, err := server.EnableStateFile(path); err != nil || n != 0 {
+		t.Fatalf("Expected an empty start, got %d, %v", n, err)
+	}
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	result := startServe(t, ctx, server, ServeOptions{Transports: []string{TransportHTTP}, Addr: addr})
+
+	server.engine.CreateGame("persisted")
+	pos, _ := game.ParsePosition("B2")
+	server.engine.MakeMove("persisted", pos, game.PlayerX)
+	cancel()
+	if err := <-result; err != nil {
+		t.Fatalf("Serve returned %v", err)
+	}
+
+	restarted := NewTicTacToeServer()
+	n, err := restarted.EnableStateFile(path)
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 game restored, got %d, %v", n, err)
+	}
+	restored, err := restarted.engine.Snapshot("persisted")
+	if err != nil || restored.MoveCount != 1 {
+		t.Errorf("Expected the game to be restored mid-play, got %+v, %v", restored, err)
+	}
+}
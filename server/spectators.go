@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+	"mcp-tic-tac-toe/game"
+)
+
+// GameEventNotification is the MCP notification method spectators receive
+const GameEventNotification = "notifications/tictactoe/game_event"
+
+// spectators tracks which client sessions watch which games
+type spectators struct {
+	mutex    sync.Mutex
+	watchers map[string]map[string]bool // Game ID -> session IDs
+}
+
+func newSpectators() *spectators {
+	return &spectators{watchers: make(map[string]map[string]bool)}
+}
+
+// watch adds a session to a game's spectators and returns how many there are
+func (sp *spectators) watch(gameID, sessionID string) int {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if sp.watchers[gameID] == nil {
+		sp.watchers[gameID] = make(map[string]bool)
+	}
+	sp.watchers[gameID][sessionID] = true
+	return len(sp.watchers[gameID])
+}
+
+// unwatch removes a session from a game's spectators, reporting whether it
+// was watching
+func (sp *spectators) unwatch(gameID, sessionID string) bool {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if !sp.watchers[gameID][sessionID] {
+		return false
+	}
+	delete(sp.watchers[gameID], sessionID)
+	if len(sp.watchers[gameID]) == 0 {
+		delete(sp.watchers, gameID)
+	}
+	return true
+}
+
+// forgetSession removes a disconnected session from every game
+func (sp *spectators) forgetSession(sessionID string) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	for gameID, sessions := range sp.watchers {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(sp.watchers, gameID)
+		}
+	}
+}
+
+// forgetGame removes every spectator of a game
+func (sp *spectators) forgetGame(gameID string) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	delete(sp.watchers, gameID)
+}
+
+// sessions returns the sessions watching a game, sorted
+func (sp *spectators) sessions(gameID string) []string {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	sessions := make([]string, 0, len(sp.watchers[gameID]))
+	for sessionID := range sp.watchers[gameID] {
+		sessions = append(sessions, sessionID)
+	}
+	sort.Strings(sessions)
+	return sessions
+}
+
+// addSpectatorHooks drops the watches of disconnected sessions
+func (s *TicTacToeServer) addSpectatorHooks(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.spectators.forgetSession(session.SessionID())
+	})
+}
+
+// notifySpectators forwards an engine event to the game's spectators. It
+// runs under the engine lock; MCP notifications are queued without
+// blocking, so a session whose queue is full, or a streamable HTTP session
+// with no listening stream open, misses the event.
+func (s *TicTacToeServer) notifySpectators(event game.Event) {
+	sessions := s.spectators.sessions(event.GameID)
+	if len(sessions) == 0 {
+		return
+	}
+
+	params := gameEventParams(event)
+	for _, sessionID := range sessions {
+		s.mcpServer.SendNotificationToSpecificClient(sessionID, GameEventNotification, params)
+	}
+	if event.Type == game.EventGameDeleted {
+		s.spectators.forgetGame(event.GameID)
+	}
+}
+
+// gameEventParams builds the notification payload for an event
+func gameEventParams(event game.Event) map[string]any {
+	g := event.Game
+	params := map[string]any{
+		"event":      string(event.Type),
+		"sequence":   event.Sequence,
+		"game_id":    event.GameID,
+		"status":     string(g.Status),
+		"fen":        g.FEN(),
+		"board":      g.Board.String(),
+		"move_count": g.MoveCount,
+	}
+	if g.Winner != game.Empty {
+		params["winner"] = string(g.Winner)
+	}
+	if !g.IsGameOver() {
+		params["to_move"] = string(g.CurrentPlayer)
+	}
+	if event.Move != nil {
+		params["move"] = map[string]any{
+			"player":   string(event.Move.Player),
+			"position": event.Move.Position.String(),
+		}
+	}
+	return params
+}
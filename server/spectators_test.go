@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"mcp-tic-tac-toe/game"
+)
+
+// fakeSession is a client session whose notifications can be read directly
+type fakeSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newFakeSession(id string) *fakeSession {
+	return &fakeSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 32)}
+}
+
+func (f *fakeSession) SessionID() string { return f.id }
+func (f *fakeSession) Initialize()       {}
+func (f *fakeSession) Initialized() bool { return true }
+func (f *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return f.notifications
+}
+
+// drain returns the notifications received so far
+func (f *fakeSession) drain() []mcp.JSONRPCNotification {
+	var received []mcp.JSONRPCNotification
+	for {
+		select {
+		case n := <-f.notifications:
+			received = append(received, n)
+		default:
+			return received
+		}
+	}
+}
+
+func watchRequest(tool, gameID string) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      tool,
+			Arguments: map[string]interface{}{"game_id": gameID},
+		},
+	}
+}
+
+func TestWatchGameTool(t *testing.T) {
+	server := NewTicTacToeServer()
+	spectator := newFakeSession("spectator-1")
+	bystander := newFakeSession("bystander")
+	ctx := context.Background()
+	for _, session := range []*fakeSession{spectator, bystander} {
+		if err := server.mcpServer.RegisterSession(ctx, session); err != nil {
+			t.Fatalf("RegisterSession failed: %v", err)
+		}
+	}
+	spectatorCtx := server.mcpServer.WithContext(ctx, spectator)
+
+	server.engine.CreateGame("exhibition")
+	result, _ := server.handleWatchGame(spectatorCtx, watchRequest("watch_game", "exhibition"))
+	if result.IsError || !strings.Contains(getTextFromResult(result), "Watching game exhibition (1 spectator(s))") {
+		t.Fatalf("Unexpected watch_game result: %s", getTextFromResult(result))
+	}
+
+	for _, move := range []string{"B2", "A1", "C2", "A2", "C1", "A3"} {
+		g, _ := server.engine.GetGame("exhibition")
+		pos, _ := game.ParsePosition(move)
+		server.engine.MakeMove("exhibition", pos, g.CurrentPlayer)
+	}
+
+	received := spectator.drain()
+	if len(received) != 7 {
+		t.Fatalf("Expected 6 move notifications and game_ended, got %d", len(received))
+	}
+	first := received[0]
+	if first.Method != GameEventNotification {
+		t.Errorf("Unexpected notification method %q", first.Method)
+	}
+	params := first.Params.AdditionalFields
+	if params["event"] != "move_made" || params["game_id"] != "exhibition" || params["to_move"] != "O" {
+		t.Errorf("Unexpected first notification: %v", params)
+	}
+	if move, ok := params["move"].(map[string]any); !ok || move["position"] != "B2" || move["player"] != "X" {
+		t.Errorf("Unexpected move in notification: %v", params["move"])
+	}
+	last := received[6].Params.AdditionalFields
+	if last["event"] != "game_ended" || last["winner"] != "O" {
+		t.Errorf("Unexpected final notification: %v", last)
+	}
+	if len(bystander.drain()) != 0 {
+		t.Error("Sessions not watching the game should not be notified")
+	}
+
+	result, _ = server.handleUnwatchGame(spectatorCtx, watchRequest("unwatch_game", "exhibition"))
+	if result.IsError {
+		t.Fatalf("unwatch_game failed: %s", getTextFromResult(result))
+	}
+	server.engine.ResetGame("exhibition")
+	if len(spectator.drain()) != 0 {
+		t.Error("Unwatched sessions should not be notified")
+	}
+
+	result, _ = server.handleUnwatchGame(spectatorCtx, watchRequest("unwatch_game", "exhibition"))
+	if !result.IsError {
+		t.Error("Unwatching a game that is not watched should fail")
+	}
+	result, _ = server.handleWatchGame(spectatorCtx, watchRequest("watch_game", "missing"))
+	if !result.IsError {
+		t.Error("Watching a missing game should fail")
+	}
+	result, _ = server.handleWatchGame(ctx, watchRequest("watch_game", "exhibition"))
+	if !result.IsError {
+		t.Error("Watching without a session should fail")
+	}
+}
+
+func TestSpectatorsForgottenOnDisconnect(t *testing.T) {
+	server := NewTicTacToeServer()
+	ctx := context.Background()
+	spectator := newFakeSession("leaving")
+	server.mcpServer.RegisterSession(ctx, spectator)
+
+	server.engine.CreateGame("one")
+	server.engine.CreateGame("two")
+	spectatorCtx := server.mcpServer.WithContext(ctx, spectator)
+	server.handleWatchGame(spectatorCtx, watchRequest("watch_game", "one"))
+	server.handleWatchGame(spectatorCtx, watchRequest("watch_game", "two"))
+
+	server.engine.DeleteGame("one")
+	if events := spectator.drain(); len(events) != 1 || events[0].Params.AdditionalFields["event"] != "game_deleted" {
+		t.Errorf("Expected a game_deleted notification, got %v", events)
+	}
+	if len(server.spectators.sessions("one")) != 0 {
+		t.Error("Deleted games should have no spectators")
+	}
+
+	server.mcpServer.UnregisterSession(ctx, "leaving")
+	if len(server.spectators.sessions("two")) != 0 {
+		t.Error("Disconnected sessions should stop watching")
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// tlsReloadCheck is how often, at most, the certificate files are checked
+// for changes. Checks happen during TLS handshakes, so an idle server does
+// no work.
+const tlsReloadCheck = time.Second
+
+// TLSOptions configures TLS on the HTTP listeners
+type TLSOptions struct {
+	CertFile     string // PEM certificate chain
+	KeyFile      string // PEM private key
+	ClientCAFile string // PEM CA bundle; if set, clients must present a certificate it signed
+}
+
+// EnableTLS serves every HTTP listener over TLS. The files are loaded now,
+// so bad ones fail fast, and reloaded whenever they change on disk: renewed
+// certificates take effect on the next connection without a restart.
+func (s *TicTacToeServer) EnableTLS(opts TLSOptions) error {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return errors.New("TLS needs both a certificate and a key file")
+	}
+	reloader := &certReloader{opts: opts, checkEvery: tlsReloadCheck}
+	if err := reloader.load(); err != nil {
+		return err
+	}
+	s.tls = reloader
+	return nil
+}
+
+// certReloader holds the TLS configuration built from the current files,
+// rebuilding it only when they change, so handshakes share one configuration
+// instead of building their own
+type certReloader struct {
+	opts       TLSOptions
+	checkEvery time.Duration
+
+	mutex   sync.Mutex
+	loaded  *tls.Config
+	stamp   string    // Sizes and modification times of the loaded files
+	checked time.Time // Last time the files were checked
+}
+
+// files returns the files the reloader watches
+func (c *certReloader) files() []string {
+	files := []string{c.opts.CertFile, c.opts.KeyFile}
+	if c.opts.ClientCAFile != "" {
+		files = append(files, c.opts.ClientCAFile)
+	}
+	return files
+}
+
+// fileStamp summarises the watched files, so changes can be spotted
+// without reading them
+func (c *certReloader) fileStamp() (string, error) {
+	stamp := ""
+	for _, file := range c.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return stamp, nil
+}
+
+// load reads and parses the files. Callers other than EnableTLS hold the
+// mutex.
+func (c *certReloader) load() error {
+	stamp, err := c.fileStamp()
+	if err != nil {
+		return fmt.Errorf("reading TLS files failed: %v", err)
+	}
+	cert, err := tls.LoadX509KeyPair(c.opts.CertFile, c.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS certificate failed: %v", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if c.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("reading client CA file failed: %v", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("client CA file %s has no certificates", c.opts.ClientCAFile)
+		}
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if clientCAs != nil {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = clientCAs
+	}
+
+	c.loaded = config
+	c.stamp = stamp
+	c.checked = time.Now()
+	return nil
+}
+
+// current returns the configuration, first reloading the files if they have
+// changed. A failed reload keeps the previous configuration, since files are
+// often replaced one at a time.
+func (c *certReloader) current() *tls.Config {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if time.Since(c.checked) >= c.checkEvery {
+		c.checked = time.Now()
+		if stamp, err := c.fileStamp(); err == nil && stamp != c.stamp {
+			if err := c.load(); err != nil {
+				slog.Warn("Keeping the previous TLS certificate", "error", err)
+			} else {
+				slog.Info("Reloaded TLS certificate", "path", c.opts.CertFile)
+			}
+		}
+	}
+	return c.loaded
+}
+
+// config returns the TLS configuration for a listener
+func (c *certReloader) config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return c.current(), nil
+		},
+	}
+}
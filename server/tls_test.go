@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA issues certificates for TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for name, usable by servers on
+// loopback and by clients
+func (ca *testCA) issue(t *testing.T, name string, serial int64) ([]byte, []byte) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeCert writes a certificate and key to dir and returns their paths
+func writeCert(t *testing.T, dir string, certPEM, keyPEM []byte) (string, string) {
+	t.Helper()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return certFile, keyFile
+}
+
+// tlsClient trusts ca and presents the given client certificate, if any
+func tlsClient(ca *testCA, clientCert *tls.Certificate) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	config := &tls.Config{RootCAs: roots}
+	if clientCert != nil {
+		config.Certificates = []tls.Certificate{*clientCert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+}
+
+// serverCertSerial returns the serial number of the certificate addr serves
+func serverCertSerial(t *testing.T, client *http.Client, url string) int64 {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	resp.Body.Close()
+	return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+}
+
+func TestEnableTLSRejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewTicTacToeServer().EnableTLS(TLSOptions{CertFile: filepath.Join(dir, "cert.pem")}); err == nil {
+		t.Error("Expected an error without a key file")
+	}
+	certFile, keyFile := writeCert(t, dir, []byte("not a cert"), []byte("not a key"))
+	if err := NewTicTacToeServer().EnableTLS(TLSOptions{CertFile: certFile, KeyFile: keyFile}); err == nil {
+		t.Error("Expected an error for invalid PEM")
+	}
+}
+
+func TestServeTLSReloadsCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, "127.0.0.1", 10)
+	certFile, keyFile := writeCert(t, dir, certPEM, keyPEM)
+
+	server := NewTicTacToeServer()
+	server.EnableREST()
+	if err := server.EnableTLS(TLSOptions{CertFile: certFile, KeyFile: keyFile}); err != nil {
+		t.Fatalf("EnableTLS failed: %v", err)
+	}
+	server.tls.checkEvery = 0
+	addr := freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startServe(t, ctx, server, ServeOptions{Transports: []string{TransportHTTP}, Addr: addr})
+
+	// Plaintext is refused
+	if resp, err := http.Get("http://" + addr + "/games"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("Expected plaintext HTTP to fail")
+		}
+	}
+
+	client := tlsClient(ca, nil)
+	if serial := serverCertSerial(t, client, "https://"+addr+"/games"); serial != 10 {
+		t.Fatalf("Expected certificate 10, got %d", serial)
+	}
+
+	// A renewed certificate is picked up without a restart
+	certPEM, keyPEM = ca.issue(t, "127.0.0.1", 11)
+	writeCert(t, dir, certPEM, keyPEM)
+	later := time.Now().Add(time.Second)
+	os.Chtimes(certFile, later, later)
+	os.Chtimes(keyFile, later, later)
+	if serial := serverCertSerial(t, client, "https://"+addr+"/games"); serial != 11 {
+		t.Errorf("Expected the renewed certificate 11, got %d", serial)
+	}
+
+	// A broken replacement keeps the previous certificate serving
+	os.WriteFile(keyFile, []byte("truncated"), 0o600)
+	later = later.Add(time.Second)
+	os.Chtimes(keyFile, later, later)
+	if serial := serverCertSerial(t, client, "https://"+addr+"/games"); serial != 11 {
+		t.Errorf("Expected certificate 11 to keep serving, got %d", serial)
+	}
+}
+
+func TestServeMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, "127.0.0.1", 20)
+	certFile, keyFile := writeCert(t, dir, certPEM, keyPEM)
+	caFile := filepath.Join(dir, "ca.pem")
+	os.WriteFile(caFile, ca.pem, 0o600)
+
+	server := NewTicTacToeServer()
+	server.EnableREST()
+	if err := server.EnableTLS(TLSOptions{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}); err != nil {
+		t.Fatalf("EnableTLS failed: %v", err)
+	}
+	server.EnableAuth(MTLSAuthenticator{})
+	addr := freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startServe(t, ctx, server, ServeOptions{Transports: []string{TransportHTTP}, Addr: addr})
+
+	// Without a client certificate the handshake fails
+	if resp, err := tlsClient(ca, nil).Get("https://" + addr + "/games"); err == nil {
+		resp.Body.Close()
+		t.Fatal("Expected a client without a certificate to be refused")
+	}
+
+	clientPEM, clientKey := ca.issue(t, "agent-42", 21)
+	clientCert, err := tls.X509KeyPair(clientPEM, clientKey)
+	if err != nil {
+		t.Fatalf("X509KeyPair failed: %v", err)
+	}
+	resp, err := tlsClient(ca, &clientCert).Post("https://"+addr+"/games", "application/json", strings.NewReader(`{"game_id":"mtls"}`))
+	if err != nil {
+		t.Fatalf("POST /games failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var view GameView
+	json.NewDecoder(resp.Body).Decode(&view)
+	if resp.StatusCode != http.StatusCreated || view.Owner != "agent-42" {
+		t.Errorf("Expected agent-42 to own the game, got %d: %+v", resp.StatusCode, view)
+	}
+}
+
+func TestTLSResumesSessionsAndNegotiatesHTTP2(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "127.0.0.1", 12)
+	certFile, keyFile := writeCert(t, t.TempDir(), certPEM, keyPEM)
+	server := NewTicTacToeServer()
+	if err := server.EnableTLS(TLSOptions{CertFile: certFile, KeyFile: keyFile}); err != nil {
+		t.Fatalf("EnableTLS failed: %v", err)
+	}
+	if server.tls.current() != server.tls.current() {
+		t.Error("Expected one configuration to be shared until the files change")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	listener = tls.NewListener(listener, server.tls.config())
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte{1})
+			conn.Close()
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	config := &tls.Config{RootCAs: roots, NextProtos: []string{"h2"}, ClientSessionCache: tls.NewLRUClientSessionCache(1)}
+	for i, wantResume := range []bool{false, true} {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+		if err != nil {
+			t.Fatalf("Dial %d failed: %v", i, err)
+		}
+		// Reading processes the session ticket sent after the handshake
+		conn.Read(make([]byte, 1))
+		state := conn.ConnectionState()
+		conn.Close()
+		if state.NegotiatedProtocol != "h2" {
+			t.Errorf("Dial %d negotiated %q, want h2", i, state.NegotiatedProtocol)
+		}
+		if state.DidResume != wantResume {
+			t.Errorf("Dial %d: DidResume = %v, want %v", i, state.DidResume, wantResume)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+// uiHeartbeat is how often the event stream sends a comment to keep idle
+// connections open through proxies
+const uiHeartbeat = 15 * time.Second
+
+// uiEventBuffer is how many events a slow browser may fall behind by before
+// it is told to reload everything
+const uiEventBuffer = 64
+
+// registerUI adds the browser UI and its event stream to a mux. The page
+// drives games through the REST API, which must be registered too.
+func (s *TicTacToeServer) registerUI(mux *http.ServeMux) {
+	static, _ := fs.Sub(uiFiles, "ui")
+	mux.Handle("GET /ui/", http.StripPrefix("/ui/", http.FileServerFS(static)))
+	mux.HandleFunc("GET /ui/events", s.handleUIEvents)
+	mux.Handle("GET /{$}", http.RedirectHandler("/ui/", http.StatusFound))
+}
+
+// handleUIEvents streams game events to the browser as server-sent events.
+// Each message's data is the same JSON document webhooks receive.
+func (s *TicTacToeServer) handleUIEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// The engine publishes under its lock, so never block it: if the
+	// browser falls behind, drop events and ask it to resync
+	events := make(chan game.Event, uiEventBuffer)
+	var dropped atomic.Bool
+	unsubscribe := s.engine.Events().Subscribe(func(event game.Event) {
+		select {
+		case events <- event:
+		default:
+			dropped.Store(true)
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(uiHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(NewWebhookPayload(event))
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.Sequence, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case <-r.Context().Done():
+			return
+		case <-shuttingDown(r.Context()):
+			return
+		}
+		if dropped.Swap(false) {
+			fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,183 @@
+// Live view of every game on the server. Game state comes from the REST API
+// and is refreshed from the /ui/events stream whenever a game changes.
+"use strict";
+
+const games = new Map(); // game_id -> GameView
+let selected = null;
+
+const $ = (id) => document.getElementById(id);
+const cells = ["A1", "B1", "C1", "A2", "B2", "C2", "A3", "B3", "C3"];
+
+async function api(method, path, body) {
+  const response = await fetch(path, {
+    method,
+    headers: body ? { "Content-Type": "application/json" } : {},
+    body: body ? JSON.stringify(body) : undefined,
+  });
+  const data = response.status === 204 ? null : await response.json();
+  if (!response.ok) {
+    throw new Error(data && data.error ? data.error : response.statusText);
+  }
+  return data;
+}
+
+// loadAll fetches every game, following list cursors
+async function loadAll() {
+  games.clear();
+  let cursor = "";
+  do {
+    const page = await api("GET", "/games?limit=100" + (cursor ? "&cursor=" + encodeURIComponent(cursor) : ""));
+    for (const summary of page.games) {
+      games.set(summary.game_id, await api("GET", "/games/" + encodeURIComponent(summary.game_id)));
+    }
+    cursor = page.next_cursor || "";
+  } while (cursor);
+  render();
+}
+
+function statusText(game) {
+  if (game.status === "won") return game.winner + " wins";
+  if (game.status === "draw") return "Draw";
+  return game.current_player + " to move";
+}
+
+function playerText(game) {
+  const name = (mark) => (game.players && game.players[mark]) || "?";
+  return "X: " + name("X") + " · O: " + name("O");
+}
+
+function renderBoard(container, game, playable) {
+  container.replaceChildren();
+  const last = game.moves.length ? game.moves[game.moves.length - 1] : null;
+  cells.forEach((name, i) => {
+    const mark = game.board[Math.floor(i / 3)][i % 3];
+    const cell = document.createElement(playable ? "button" : "div");
+    cell.className = "cell" + (mark ? " " + mark : "") + (name === last ? " last" : "");
+    cell.textContent = mark;
+    cell.title = name;
+    if (playable && !mark && game.status === "ongoing") {
+      cell.classList.add("playable");
+      cell.addEventListener("click", () => play(game.game_id, name));
+    }
+    container.appendChild(cell);
+  });
+}
+
+function render() {
+  const list = $("games");
+  const sorted = [...games.values()].sort((a, b) => b.updated_at.localeCompare(a.updated_at));
+  $("empty").hidden = sorted.length > 0;
+  list.replaceChildren();
+  for (const game of sorted) {
+    const card = document.createElement("div");
+    card.className = "card";
+    card.dataset.id = game.game_id;
+    const title = document.createElement("h3");
+    title.textContent = game.game_id;
+    const board = document.createElement("div");
+    board.className = "board";
+    renderBoard(board, game, false);
+    const status = document.createElement("p");
+    status.className = "small";
+    status.textContent = statusText(game) + " · " + playerText(game);
+    card.append(title, board, status);
+    card.addEventListener("click", () => select(game.game_id));
+    list.appendChild(card);
+  }
+  renderDetail();
+}
+
+function renderDetail() {
+  const game = selected && games.get(selected);
+  $("detail").hidden = !game;
+  if (!game) return;
+  $("detail-title").textContent = game.game_id;
+  $("detail-players").textContent = playerText(game);
+  renderBoard($("detail-board"), game, true);
+  $("detail-status").textContent = statusText(game);
+  $("detail-moves").textContent = game.moves.length ? "Moves: " + game.moves.join(" ") : "No moves yet";
+}
+
+function select(id) {
+  selected = id;
+  $("detail-error").textContent = "";
+  renderDetail();
+  $("detail").scrollIntoView({ behavior: "smooth" });
+}
+
+async function play(id, position) {
+  const seat = $("seat").value;
+  $("detail-error").textContent = "";
+  try {
+    games.set(id, await api("POST", "/games/" + encodeURIComponent(id) + "/moves", seat ? { position, player: seat } : { position }));
+    render();
+  } catch (err) {
+    $("detail-error").textContent = err.message;
+  }
+}
+
+function flash(id) {
+  const card = document.querySelector('.card[data-id="' + CSS.escape(id) + '"]');
+  if (card) {
+    card.classList.remove("flash");
+    void card.offsetWidth;
+    card.classList.add("flash");
+  }
+}
+
+function connect() {
+  const events = new EventSource("/ui/events");
+  events.addEventListener("open", () => {
+    $("connection").textContent = "live";
+    $("connection").className = "online";
+    loadAll().catch((err) => console.error(err));
+  });
+  events.addEventListener("error", () => {
+    $("connection").textContent = "reconnecting…";
+    $("connection").className = "offline";
+  });
+  events.addEventListener("message", async (message) => {
+    const event = JSON.parse(message.data);
+    if (event.event === "game_deleted") {
+      games.delete(event.game_id);
+      render();
+      return;
+    }
+    try {
+      games.set(event.game_id, await api("GET", "/games/" + encodeURIComponent(event.game_id)));
+      render();
+      flash(event.game_id);
+    } catch (err) {
+      // The game may have been deleted in the meantime
+      games.delete(event.game_id);
+      render();
+    }
+  });
+  // Sent when events were dropped because this page fell behind
+  events.addEventListener("resync", () => loadAll().catch((err) => console.error(err)));
+}
+
+$("close-detail").addEventListener("click", () => {
+  selected = null;
+  renderDetail();
+});
+
+$("new-game").addEventListener("submit", async (e) => {
+  e.preventDefault();
+  const form = new FormData(e.target);
+  const body = {};
+  for (const [key, value] of form) {
+    if (value) body[key] = value;
+  }
+  try {
+    const game = await api("POST", "/games", body);
+    games.set(game.game_id, game);
+    e.target.reset();
+    render();
+    select(game.game_id);
+  } catch (err) {
+    alert(err.message);
+  }
+});
+
+connect();
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUIServesEmbeddedPage(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableUI()
+	mux := server.httpMux("/mcp", http.NotFoundHandler())
+
+	for path, want := range map[string]string{
+		"/ui/":          "<title>Tic-Tac-Toe</title>",
+		"/ui/app.js":    "EventSource",
+		"/ui/style.css": ".board",
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("GET %s: expected 200 containing %q, got %d", path, want, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/ui/" {
+		t.Errorf("Expected / to redirect to /ui/, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// The UI needs the REST API even if it wasn't enabled explicitly
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/games", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the REST API to be served with the UI, got %d", rec.Code)
+	}
+}
+
+func TestUIEventStream(t *testing.T) {
+	server := NewTicTacToeServer()
+	server.EnableUI()
+	httpServer := httptest.NewServer(server.httpMux("/mcp", http.NotFoundHandler()))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", httpServer.URL+"/ui/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Opening event stream failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	// Wait for the stream to be established before publishing
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("Unexpected first line %q", line)
+	}
+
+	server.engine.CreateGame("streamed")
+	var data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Reading event stream failed: %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			data = line
+		}
+	}
+	if !strings.Contains(data, `"event":"game_created"`) || !strings.Contains(data, `"game_id":"streamed"`) {
+		t.Errorf("Unexpected event data %q", data)
+	}
+}
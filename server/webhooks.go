@@ -0,0 +1,469 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+// Webhook request headers. The signature is "sha256=" followed by the hex
+// HMAC-SHA256, keyed with the shared secret, of "<timestamp>.<body>".
+const (
+	WebhookEventHeader     = "X-TicTacToe-Event"
+	WebhookDeliveryHeader  = "X-TicTacToe-Delivery"
+	WebhookTimestampHeader = "X-TicTacToe-Timestamp"
+	WebhookSignatureHeader = "X-TicTacToe-Signature"
+)
+
+// webhookQueueSize bounds the events waiting per endpoint; when a slow
+// endpoint falls this far behind, further events go to the dead-letter log
+const webhookQueueSize = 1024
+
+// Duration is a time.Duration read from config files as a string like "2s"
+type Duration time.Duration
+
+// UnmarshalText parses a Go duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// WebhookConfig configures outbound webhooks. Zero values use the defaults
+// noted on each field.
+type WebhookConfig struct {
+	URLs           []string         `json:"urls"`
+	Secret         string           `json:"secret"`          // Signing key; requests are unsigned if empty
+	Events         []game.EventType `json:"events"`          // Defaults to game_created, move_made and game_ended
+	MaxAttempts    int              `json:"max_attempts"`    // Defaults to 5
+	InitialBackoff Duration         `json:"initial_backoff"` // Defaults to 500ms, doubling per retry
+	MaxBackoff     Duration         `json:"max_backoff"`     // Defaults to 30s
+	Timeout        Duration         `json:"timeout"`         // Per request, defaults to 10s
+	DeadLetterFile string           `json:"dead_letter_file"`
+}
+
+// LoadWebhookConfig reads a JSON webhook config file
+func LoadWebhookConfig(path string) (WebhookConfig, error) {
+	var cfg WebhookConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid webhook config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// withDefaults fills in unset fields
+func (c WebhookConfig) withDefaults() WebhookConfig {
+	if len(c.Events) == 0 {
+		c.Events = []game.EventType{game.EventGameCreated, game.EventMoveMade, game.EventGameEnded}
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = Duration(500 * time.Millisecond)
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = Duration(30 * time.Second)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = Duration(10 * time.Second)
+	}
+	return c
+}
+
+// WebhookPayload is the JSON body POSTed for each event
+type WebhookPayload struct {
+	Event     game.EventType    `json:"event"`
+	Sequence  uint64            `json:"sequence"`
+	Time      time.Time         `json:"time"`
+	GameID    string            `json:"game_id"`
+	Status    game.GameStatus   `json:"status"`
+	Winner    game.Player       `json:"winner,omitempty"`
+	ToMove    game.Player       `json:"to_move,omitempty"`
+	Variant   game.Variant      `json:"variant"`
+	Players   map[string]string `json:"players,omitempty"`
+	FEN       string            `json:"fen"`
+	Moves     []string          `json:"moves"`
+	Move      *WebhookMove      `json:"move,omitempty"`
+	MoveCount int               `json:"move_count"`
+}
+
+// WebhookMove is the move that triggered a move_made or game_ended event
+type WebhookMove struct {
+	Player   game.Player   `json:"player"`
+	Position game.Position `json:"position"`
+}
+
+// NewWebhookPayload converts an engine event to its webhook body
+func NewWebhookPayload(event game.Event) WebhookPayload {
+	g := event.Game
+	payload := WebhookPayload{
+		Event:     event.Type,
+		Sequence:  event.Sequence,
+		Time:      event.Time,
+		GameID:    event.GameID,
+		Status:    g.Status,
+		Winner:    g.Winner,
+		Variant:   g.Variant,
+		FEN:       g.FEN(),
+		Moves:     make([]string, len(g.Moves)),
+		MoveCount: g.MoveCount,
+	}
+	if !g.IsGameOver() {
+		payload.ToMove = g.CurrentPlayer
+	}
+	if len(g.Players) > 0 {
+		payload.Players = make(map[string]string, len(g.Players))
+		for player, name := range g.Players {
+			payload.Players[string(player)] = name
+		}
+	}
+	for i, move := range g.Moves {
+		payload.Moves[i] = move.Position.String()
+	}
+	if event.Move != nil {
+		payload.Move = &WebhookMove{Player: event.Move.Player, Position: event.Move.Position}
+	}
+	return payload
+}
+
+// SignWebhook returns the signature header value for a request body
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook reports whether signature is valid for the body, for use by
+// receivers
+func VerifyWebhook(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}
+
+// webhookDelivery is one event queued for one endpoint
+type webhookDelivery struct {
+	id        string // Random, and the same for every attempt
+	eventType game.EventType
+	sequence  uint64
+	body      []byte
+}
+
+// newDeliveryID returns a random delivery ID. Unlike event sequence numbers,
+// which restart with the server, it stays unique across restarts.
+func newDeliveryID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// errWebhooksClosed is the dead-letter error for deliveries abandoned when
+// Close runs out of time
+var errWebhooksClosed = errors.New("dispatcher closed before delivery")
+
+// DeadLetter is a line in the dead-letter log: a delivery that failed every
+// attempt or was dropped
+type DeadLetter struct {
+	Time     time.Time       `json:"time"`
+	URL      string          `json:"url"`
+	Delivery string          `json:"delivery"`
+	Event    game.EventType  `json:"event"`
+	Sequence uint64          `json:"sequence"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// WebhookDispatcher POSTs engine events to the configured URLs. Each URL has
+// its own queue and worker, so a slow endpoint delays only itself and every
+// endpoint receives events in order.
+type WebhookDispatcher struct {
+	cfg     WebhookConfig
+	client  *http.Client
+	events  map[game.EventType]bool
+	queues  map[string]chan webhookDelivery
+	wg      sync.WaitGroup
+	closing sync.Once
+	// stop is cancelled when Close runs out of time, aborting requests and
+	// retries
+	stop   context.Context
+	cancel context.CancelFunc
+	// unsubscribe detaches Handle from the event bus, if it was attached
+	unsubscribe func()
+
+	// Dead letters are written by their own goroutine, because Handle runs
+	// while the engine holds its lock
+	deadMutex   sync.Mutex
+	deadPending []DeadLetter
+	deadWake    chan struct{} // Signals the writer that deadPending has grown
+	deadDone    chan struct{} // Closed once the workers have stopped
+	deadWritten chan struct{} // Closed when the writer has finished
+	deadLetter  *os.File      // Nil when dead letters are only logged
+}
+
+// NewWebhookDispatcher starts a worker per configured URL
+func NewWebhookDispatcher(cfg WebhookConfig) (*WebhookDispatcher, error) {
+	cfg = cfg.withDefaults()
+	d := &WebhookDispatcher{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Duration(cfg.Timeout)},
+		events: make(map[game.EventType]bool),
+		queues: make(map[string]chan webhookDelivery),
+
+		deadWake:    make(chan struct{}, 1),
+		deadDone:    make(chan struct{}),
+		deadWritten: make(chan struct{}),
+	}
+	d.stop, d.cancel = context.WithCancel(context.Background())
+	for _, eventType := range cfg.Events {
+		d.events[eventType] = true
+	}
+
+	if cfg.DeadLetterFile != "" {
+		file, err := os.OpenFile(cfg.DeadLetterFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening dead-letter log: %v", err)
+		}
+		d.deadLetter = file
+	}
+	go d.writeDeadLetters()
+
+	for _, url := range cfg.URLs {
+		if _, exists := d.queues[url]; exists {
+			continue
+		}
+		queue := make(chan webhookDelivery, webhookQueueSize)
+		d.queues[url] = queue
+		d.wg.Add(1)
+		go d.worker(url, queue)
+	}
+	return d, nil
+}
+
+// EnableWebhooks sends the server's game events to the configured webhooks.
+// Close the returned dispatcher to deliver pending events.
+func (s *TicTacToeServer) EnableWebhooks(cfg WebhookConfig) (*WebhookDispatcher, error) {
+	d, err := NewWebhookDispatcher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	d.unsubscribe = s.engine.Events().Subscribe(d.Handle)
+	return d, nil
+}
+
+// Handle queues an event for every URL. It never blocks: it runs while the
+// engine holds its lock, so an event for a full queue is dead-lettered.
+func (d *WebhookDispatcher) Handle(event game.Event) {
+	if !d.events[event.Type] {
+		return
+	}
+	body, err := json.Marshal(NewWebhookPayload(event))
+	if err != nil {
+		slog.Error("Encoding webhook payload failed", "event", event.Type, "sequence", event.Sequence, "error", err)
+		return
+	}
+	delivery := webhookDelivery{id: newDeliveryID(), eventType: event.Type, sequence: event.Sequence, body: body}
+	for url, queue := range d.queues {
+		select {
+		case queue <- delivery:
+		default:
+			d.deadLetterDelivery(url, delivery, 0, fmt.Errorf("queue full"))
+		}
+	}
+}
+
+// Close stops accepting events and waits for queued deliveries to finish,
+// including retries, then closes the dead-letter log. If ctx ends first,
+// requests and retries are abandoned, whatever is left is dead-lettered and
+// ctx's error is returned.
+func (d *WebhookDispatcher) Close(ctx context.Context) error {
+	var err error
+	d.closing.Do(func() {
+		// Once unsubscribe returns no Handle call is in progress, so the
+		// queues can be closed safely
+		if d.unsubscribe != nil {
+			d.unsubscribe()
+		}
+		for _, queue := range d.queues {
+			close(queue)
+		}
+
+		workersDone := make(chan struct{})
+		go func() {
+			d.wg.Wait()
+			close(workersDone)
+		}()
+		select {
+		case <-workersDone:
+		case <-ctx.Done():
+			err = ctx.Err()
+			d.cancel()
+			<-workersDone
+		}
+		d.cancel()
+
+		close(d.deadDone)
+		<-d.deadWritten
+		if d.deadLetter != nil {
+			if closeErr := d.deadLetter.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+// worker delivers one URL's queue in order. Once the dispatcher is stopped
+// it dead-letters the rest of the queue.
+func (d *WebhookDispatcher) worker(url string, queue <-chan webhookDelivery) {
+	defer d.wg.Done()
+	for delivery := range queue {
+		if d.stop.Err() != nil {
+			d.deadLetterDelivery(url, delivery, 0, errWebhooksClosed)
+			continue
+		}
+		d.deliver(url, delivery)
+	}
+}
+
+// deliver POSTs a delivery, retrying network errors, 429s and 5xx responses
+// with exponential backoff
+func (d *WebhookDispatcher) deliver(url string, delivery webhookDelivery) {
+	backoff := time.Duration(d.cfg.InitialBackoff)
+	for attempt := 1; ; attempt++ {
+		retry, err := d.post(url, delivery)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == d.cfg.MaxAttempts {
+			d.deadLetterDelivery(url, delivery, attempt, err)
+			return
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-d.stop.Done():
+			timer.Stop()
+			d.deadLetterDelivery(url, delivery, attempt, err)
+			return
+		}
+		backoff = min(2*backoff, time.Duration(d.cfg.MaxBackoff))
+	}
+}
+
+// post makes a single delivery attempt and reports whether a failure is
+// worth retrying
+func (d *WebhookDispatcher) post(url string, delivery webhookDelivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(d.stop, http.MethodPost, url, bytes.NewReader(delivery.body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mcp-tic-tac-toe-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, string(delivery.eventType))
+	req.Header.Set(WebhookDeliveryHeader, delivery.id)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if d.cfg.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(d.cfg.Secret, timestamp, delivery.body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+}
+
+// deadLetterDelivery queues a delivery that will not be retried for the
+// dead-letter writer. It never blocks.
+func (d *WebhookDispatcher) deadLetterDelivery(url string, delivery webhookDelivery, attempts int, err error) {
+	letter := DeadLetter{
+		Time:     time.Now(),
+		URL:      url,
+		Delivery: delivery.id,
+		Event:    delivery.eventType,
+		Sequence: delivery.sequence,
+		Attempts: attempts,
+		Error:    err.Error(),
+		Payload:  delivery.body,
+	}
+	d.deadMutex.Lock()
+	d.deadPending = append(d.deadPending, letter)
+	d.deadMutex.Unlock()
+
+	select {
+	case d.deadWake <- struct{}{}:
+	default:
+	}
+}
+
+// writeDeadLetters logs dead letters and appends them to the dead-letter
+// file until the workers have stopped
+func (d *WebhookDispatcher) writeDeadLetters() {
+	defer close(d.deadWritten)
+	for {
+		select {
+		case <-d.deadWake:
+			d.flushDeadLetters()
+		case <-d.deadDone:
+			d.flushDeadLetters()
+			return
+		}
+	}
+}
+
+// flushDeadLetters writes out the pending dead letters
+func (d *WebhookDispatcher) flushDeadLetters() {
+	d.deadMutex.Lock()
+	letters := d.deadPending
+	d.deadPending = nil
+	d.deadMutex.Unlock()
+
+	for _, letter := range letters {
+		slog.Warn("Webhook delivery failed", "event", letter.Event, "delivery", letter.Delivery, "url", letter.URL, "attempts", letter.Attempts, "error", letter.Error)
+		if d.deadLetter == nil {
+			continue
+		}
+		line, _ := json.Marshal(letter)
+		d.deadLetter.Write(append(line, '\n'))
+	}
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-tic-tac-toe/game"
+)
+
+// webhookReceiver is a local stand-in for a webhook endpoint. It fails the
+// first failures requests with status 503.
+type webhookReceiver struct {
+	mutex    sync.Mutex
+	failures int
+	status   int
+	payloads []WebhookPayload
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+		return
+	}
+	var payload WebhookPayload
+	json.Unmarshal(body, &payload)
+	r.payloads = append(r.payloads, payload)
+	r.headers = append(r.headers, req.Header.Clone())
+	r.bodies = append(r.bodies, body)
+}
+
+func TestWebhooksDeliverSignedEvents(t *testing.T) {
+	receiver := &webhookReceiver{failures: 2}
+	endpoint := httptest.NewServer(receiver)
+	defer endpoint.Close()
+
+	server := NewTicTacToeServer()
+	dispatcher, err := server.EnableWebhooks(WebhookConfig{
+		URLs:           []string{endpoint.URL},
+		Secret:         "s3cret",
+		InitialBackoff: Duration(time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("EnableWebhooks failed: %v", err)
+	}
+
+	server.engine.CreateGame("hooked")
+	for _, move := range []string{"B2", "A1", "C2", "A2", "C1", "A3"} {
+		g, _ := server.engine.GetGame("hooked")
+		pos, _ := game.ParsePosition(move)
+		if _, err := server.engine.MakeMove("hooked", pos, g.CurrentPlayer); err != nil {
+			t.Fatalf("MakeMove failed: %v", err)
+		}
+	}
+	// Resets are not sent by default
+	server.engine.ResetGame("hooked")
+	dispatcher.Close(context.Background())
+
+	if len(receiver.payloads) != 8 {
+		t.Fatalf("Expected 8 deliveries (created, 6 moves, ended), got %d", len(receiver.payloads))
+	}
+	first, last := receiver.payloads[0], receiver.payloads[7]
+	if first.Event != game.EventGameCreated || first.GameID != "hooked" || first.Status != game.StatusOngoing {
+		t.Errorf("Unexpected first payload: %+v", first)
+	}
+	if last.Event != game.EventGameEnded || last.Winner != game.PlayerO || last.Move == nil || last.Move.Position.String() != "A3" {
+		t.Errorf("Unexpected last payload: %+v", last)
+	}
+	if move := receiver.payloads[1]; move.Event != game.EventMoveMade || len(move.Moves) != 1 || move.Moves[0] != "B2" {
+		t.Errorf("Unexpected move payload: %+v", move)
+	}
+	for i := 1; i < len(receiver.payloads); i++ {
+		if receiver.payloads[i].Sequence <= receiver.payloads[i-1].Sequence {
+			t.Fatalf("Deliveries out of order at %d", i)
+		}
+	}
+
+	header := receiver.headers[0]
+	if header.Get(WebhookEventHeader) != "game_created" || len(header.Get(WebhookDeliveryHeader)) != 32 {
+		t.Errorf("Unexpected headers: %v", header)
+	}
+	if header.Get(WebhookDeliveryHeader) == receiver.headers[1].Get(WebhookDeliveryHeader) {
+		t.Error("Expected a distinct delivery ID per event")
+	}
+	if !VerifyWebhook("s3cret", header.Get(WebhookTimestampHeader), receiver.bodies[0], header.Get(WebhookSignatureHeader)) {
+		t.Error("Webhook signature did not verify")
+	}
+	if VerifyWebhook("wrong", header.Get(WebhookTimestampHeader), receiver.bodies[0], header.Get(WebhookSignatureHeader)) {
+		t.Error("Webhook signature verified with the wrong secret")
+	}
+
+	// Once closed, the dispatcher no longer receives events
+	server.engine.CreateGame("after-close")
+	if len(receiver.payloads) != 8 {
+		t.Error("Events after Close should not be delivered")
+	}
+}
+
+func TestWebhooksDeadLetter(t *testing.T) {
+	flaky := httptest.NewServer(&webhookReceiver{failures: 100})
+	defer flaky.Close()
+	rejecting := httptest.NewServer(&webhookReceiver{status: http.StatusBadRequest})
+	defer rejecting.Close()
+
+	deadLetterFile := filepath.Join(t.TempDir(), "dead.jsonl")
+	server := NewTicTacToeServer()
+	dispatcher, err := server.EnableWebhooks(WebhookConfig{
+		URLs:           []string{flaky.URL, rejecting.URL},
+		Events:         []game.EventType{game.EventGameDeleted},
+		MaxAttempts:    3,
+		InitialBackoff: Duration(time.Millisecond),
+		DeadLetterFile: deadLetterFile,
+	})
+	if err != nil {
+		t.Fatalf("EnableWebhooks failed: %v", err)
+	}
+
+	server.engine.CreateGame("doomed")
+	server.engine.DeleteGame("doomed")
+	dispatcher.Close(context.Background())
+
+	file, err := os.Open(deadLetterFile)
+	if err != nil {
+		t.Fatalf("Opening dead-letter log failed: %v", err)
+	}
+	defer file.Close()
+
+	attempts := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			t.Fatalf("Invalid dead-letter line %q: %v", scanner.Text(), err)
+		}
+		if letter.Event != game.EventGameDeleted || letter.Delivery == "" || len(letter.Payload) == 0 {
+			t.Errorf("Unexpected dead letter: %+v", letter)
+		}
+		attempts[letter.URL] = letter.Attempts
+	}
+	// Server errors are retried; other client errors are not
+	if attempts[flaky.URL] != 3 || attempts[rejecting.URL] != 1 || len(attempts) != 2 {
+		t.Errorf("Unexpected dead letters: %v", attempts)
+	}
+}
+
+func TestWebhooksCloseDeadline(t *testing.T) {
+	flaky := httptest.NewServer(&webhookReceiver{failures: 100})
+	defer flaky.Close()
+
+	deadLetterFile := filepath.Join(t.TempDir(), "dead.jsonl")
+	server := NewTicTacToeServer()
+	dispatcher, err := server.EnableWebhooks(WebhookConfig{
+		URLs:           []string{flaky.URL},
+		InitialBackoff: Duration(time.Minute),
+		DeadLetterFile: deadLetterFile,
+	})
+	if err != nil {
+		t.Fatalf("EnableWebhooks failed: %v", err)
+	}
+	server.engine.CreateGame("first")
+	server.engine.CreateGame("second")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := dispatcher.Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected Close to report the deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close took %v despite its deadline", elapsed)
+	}
+
+	// The retrying delivery and the queued one are both dead-lettered
+	data, err := os.ReadFile(deadLetterFile)
+	if err != nil {
+		t.Fatalf("Reading dead-letter log failed: %v", err)
+	}
+	var letters []DeadLetter
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var letter DeadLetter
+		if err := json.Unmarshal(line, &letter); err != nil {
+			t.Fatalf("Invalid dead-letter line %q: %v", line, err)
+		}
+		letters = append(letters, letter)
+	}
+	if len(letters) != 2 || letters[0].Attempts != 1 || letters[1].Attempts != 0 || letters[1].Error != errWebhooksClosed.Error() {
+		t.Errorf("Unexpected dead letters: %+v", letters)
+	}
+}
+
+func TestLoadWebhookConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	os.WriteFile(path, []byte(`{
+		"urls": ["http://localhost:9000/hook"],
+		"secret": "abc",
+		"events": ["game_ended"],
+		"initial_backoff": "250ms",
+		"max_attempts": 2
+	}`), 0o644)
+
+	cfg, err := LoadWebhookConfig(path)
+	if err != nil {
+		t.Fatalf("LoadWebhookConfig failed: %v", err)
+	}
+	if len(cfg.URLs) != 1 || cfg.Secret != "abc" || cfg.MaxAttempts != 2 ||
+		time.Duration(cfg.InitialBackoff) != 250*time.Millisecond || cfg.Events[0] != game.EventGameEnded {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	os.WriteFile(path, []byte(`{"initial_backoff": "soon"}`), 0o644)
+	if _, err := LoadWebhookConfig(path); err == nil {
+		t.Error("Expected an error for an invalid duration")
+	}
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WebSocket keepalive: the server pings every wsPingInterval and drops
+// connections that have not answered within wsPongTimeout
+const (
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 60 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	wsMaxMessage   = 1 << 20
+	// wsMaxInFlight caps the requests handled at once per connection; the
+	// connection is not read while the cap is reached
+	wsMaxInFlight = 16
+)
+
+// wsSession is an MCP client session on one WebSocket connection
+type wsSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+}
+
+func (w *wsSession) SessionID() string { return w.id }
+
+func (w *wsSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return w.notifications
+}
+
+func (w *wsSession) Initialize() { w.initialized.Store(true) }
+
+func (w *wsSession) Initialized() bool { return w.initialized.Load() }
+
+var _ server.ClientSession = (*wsSession)(nil)
+
+// WebSocketHandler speaks MCP JSON-RPC over WebSocket: each text message
+// from the client is one request or notification, and each response or
+// server notification is sent as one text message. Requests are handled
+// concurrently, so responses may arrive out of order; match them by ID.
+// Browsers may only connect from the same origin.
+func (s *TicTacToeServer) WebSocketHandler() http.Handler {
+	upgrader := websocket.Upgrader{CheckOrigin: sameOrigin}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already written an HTTP error
+			return
+		}
+		s.serveWebSocketConn(withTransport(r.Context(), TransportWebSocket), conn)
+	})
+}
+
+// sameOrigin accepts requests without an Origin header, which come from
+// non-browser clients, and requests whose Origin host matches the Host header
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// serveWebSocketConn runs an MCP session until the connection closes
+func (s *TicTacToeServer) serveWebSocketConn(ctx context.Context, conn *websocket.Conn) {
+	defer conn.Close()
+
+	idBytes := make([]byte, 8)
+	rand.Read(idBytes)
+	session := &wsSession{
+		id:            "ws-" + hex.EncodeToString(idBytes),
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+	}
+	if err := s.mcpServer.RegisterSession(ctx, session); err != nil {
+		slog.Error("Registering WebSocket session failed", "error", err)
+		return
+	}
+	defer s.mcpServer.UnregisterSession(ctx, session.id)
+
+	ctx, cancel := context.WithCancel(s.mcpServer.WithContext(ctx, session))
+	defer cancel()
+
+	// A single writer owns the connection's write side
+	outgoing := make(chan any, 16)
+	inFlight := make(chan struct{}, wsMaxInFlight)
+	var handlers sync.WaitGroup
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		shutdown := shuttingDown(ctx)
+		ping := time.NewTicker(wsPingInterval)
+		defer ping.Stop()
+		for {
+			var err error
+			select {
+			case message := <-outgoing:
+				conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+				err = conn.WriteJSON(message)
+			case notification := <-session.notifications:
+				conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+				err = conn.WriteJSON(notification)
+			case <-ping.C:
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+			case <-shutdown:
+				// Flush queued notifications, such as the shutdown notice,
+				// then ask the client to close; the read loop ends when it does
+				for flushed := false; !flushed && err == nil; {
+					select {
+					case notification := <-session.notifications:
+						conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+						err = conn.WriteJSON(notification)
+					default:
+						flushed = true
+					}
+				}
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(wsWriteTimeout))
+				shutdown = nil
+			case <-ctx.Done():
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteTimeout))
+				return
+			}
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	conn.SetReadLimit(wsMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+read:
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		if messageType != websocket.TextMessage {
+			conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+			continue
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			break read
+		}
+		// Waiting for a free slot does not count against the client
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		handlers.Add(1)
+		go func(raw json.RawMessage) {
+			defer handlers.Done()
+			defer func() { <-inFlight }()
+			response := s.mcpServer.HandleMessage(ctx, raw)
+			if response == nil {
+				return
+			}
+			select {
+			case outgoing <- response:
+			case <-ctx.Done():
+			}
+		}(data)
+	}
+
+	cancel()
+	handlers.Wait()
+	<-writerDone
+}
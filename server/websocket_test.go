@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// wsClient is a minimal MCP client over WebSocket
+type wsClient struct {
+	t      *testing.T
+	conn   *websocket.Conn
+	nextID int
+	// notifications received while waiting for responses
+	notifications []map[string]any
+}
+
+func dialWebSocket(t *testing.T, url string) *wsClient {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return &wsClient{t: t, conn: conn}
+}
+
+// call sends a request and returns its result, collecting notifications
+func (c *wsClient) call(method string, params any) map[string]any {
+	c.t.Helper()
+	c.nextID++
+	id := c.nextID
+	if err := c.conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params}); err != nil {
+		c.t.Fatalf("Write failed: %v", err)
+	}
+	for {
+		var message map[string]any
+		if err := c.conn.ReadJSON(&message); err != nil {
+			c.t.Fatalf("Read failed: %v", err)
+		}
+		if _, isResponse := message["id"]; !isResponse {
+			c.notifications = append(c.notifications, message)
+			continue
+		}
+		if message["id"].(float64) != float64(id) {
+			c.t.Fatalf("Unexpected response %v", message)
+		}
+		if message["error"] != nil {
+			c.t.Fatalf("%s failed: %v", method, message["error"])
+		}
+		return message["result"].(map[string]any)
+	}
+}
+
+// notify sends a notification, which gets no response
+func (c *wsClient) notify(method string) {
+	c.conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "method": method})
+}
+
+func (c *wsClient) callTool(name string, arguments map[string]any) string {
+	c.t.Helper()
+	result := c.call("tools/call", map[string]any{"name": name, "arguments": arguments})
+	content := result["content"].([]any)
+	return content[0].(map[string]any)["text"].(string)
+}
+
+func initializeWebSocket(c *wsClient) {
+	c.t.Helper()
+	result := c.call("initialize", map[string]any{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "test", "version": "1.0"},
+	})
+	if info, ok := result["serverInfo"].(map[string]any); !ok || info["name"] != "Tic-Tac-Toe Game Server" {
+		c.t.Fatalf("Unexpected initialize result %v", result)
+	}
+	c.notify("notifications/initialized")
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	server := NewTicTacToeServer()
+	httpServer := httptest.NewServer(server.httpMux("/ws", server.WebSocketHandler()))
+	defer httpServer.Close()
+
+	player := dialWebSocket(t, httpServer.URL)
+	defer player.conn.Close()
+	spectator := dialWebSocket(t, httpServer.URL)
+	defer spectator.conn.Close()
+	initializeWebSocket(player)
+	initializeWebSocket(spectator)
+
+	tools := player.call("tools/list", map[string]any{})["tools"].([]any)
+	if len(tools) == 0 {
+		t.Fatal("Expected tools to be listed")
+	}
+
+	if text := player.callTool("new_game", map[string]any{"game_id": "duplex"}); !strings.Contains(text, "duplex") {
+		t.Fatalf("Unexpected new_game result %q", text)
+	}
+	if text := spectator.callTool("watch_game", map[string]any{"game_id": "duplex"}); !strings.Contains(text, "Watching game duplex") {
+		t.Fatalf("Unexpected watch_game result %q", text)
+	}
+	player.callTool("make_move", map[string]any{"game_id": "duplex", "position": "B2", "player": "X"})
+
+	// The move is pushed to the spectator without it polling
+	var notification map[string]any
+	if err := spectator.conn.ReadJSON(&notification); err != nil {
+		t.Fatalf("Reading notification failed: %v", err)
+	}
+	params, _ := json.Marshal(notification["params"])
+	if notification["method"] != GameEventNotification || !strings.Contains(string(params), `"position":"B2"`) {
+		t.Errorf("Unexpected notification %v", notification)
+	}
+
+	spectator.conn.Close()
+	// Closing the connection ends the session and its watches
+	deadline := time.Now().Add(2 * time.Second)
+	for len(server.spectators.sessions("duplex")) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Spectator was not forgotten after disconnecting")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketOriginCheck(t *testing.T) {
+	server := NewTicTacToeServer()
+	httpServer := httptest.NewServer(server.httpMux("/ws", server.WebSocketHandler()))
+	defer httpServer.Close()
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+
+	testCases := []struct {
+		origin string
+		allow  bool
+	}{
+		{"", true},
+		{httpServer.URL, true},
+		{"http://evil.example", false},
+		{"http://" + strings.TrimPrefix(httpServer.URL, "http://") + ".evil.example", false},
+	}
+	for _, tc := range testCases {
+		header := http.Header{}
+		if tc.origin != "" {
+			header.Set("Origin", tc.origin)
+		}
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+		if tc.allow {
+			if err != nil {
+				t.Errorf("Origin %q was refused: %v", tc.origin, err)
+				continue
+			}
+			conn.Close()
+			continue
+		}
+		if err == nil {
+			conn.Close()
+			t.Errorf("Origin %q was accepted", tc.origin)
+		} else if resp == nil || resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected 403 for origin %q, got %v", tc.origin, err)
+		}
+	}
+}
+
+func TestWebSocketInFlightLimit(t *testing.T) {
+	server := NewTicTacToeServer()
+	release := make(chan struct{})
+	var running, peak atomic.Int32
+	server.mcpServer.AddTool(mcp.NewTool("block"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return mcp.NewToolResultText("done"), nil
+	})
+	httpServer := httptest.NewServer(server.httpMux("/ws", server.WebSocketHandler()))
+	defer httpServer.Close()
+
+	client := dialWebSocket(t, httpServer.URL)
+	defer client.conn.Close()
+	initializeWebSocket(client)
+
+	calls := wsMaxInFlight + 4
+	for id := 1; id <= calls; id++ {
+		client.conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "id": id, "method": "tools/call",
+			"params": map[string]any{"name": "block", "arguments": map[string]any{}}})
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for running.Load() < wsMaxInFlight && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := peak.Load(); got != wsMaxInFlight {
+		t.Errorf("Expected %d calls in flight, got %d", wsMaxInFlight, got)
+	}
+
+	// The rest run once slots free up
+	close(release)
+	for received := 0; received < calls; {
+		var message map[string]any
+		if err := client.conn.ReadJSON(&message); err != nil {
+			t.Fatalf("Read failed after %d responses: %v", received, err)
+		}
+		if _, isResponse := message["id"]; isResponse {
+			received++
+		}
+	}
+}